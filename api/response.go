@@ -0,0 +1,95 @@
+package api
+
+import (
+	"progression/league"
+	"progression/repository"
+)
+
+type standingResponse struct {
+	PlayerID    string `json:"player_id"`
+	Points      int    `json:"points"`
+	MatchWins   int    `json:"match_wins"`
+	MatchLosses int    `json:"match_losses"`
+	MatchDraws  int    `json:"match_draws"`
+	GameWins    int    `json:"game_wins"`
+	GameLosses  int    `json:"game_losses"`
+	GameDraws   int    `json:"game_draws"`
+	Dropped     bool   `json:"dropped"`
+}
+
+type pairingResponse struct {
+	Round    int    `json:"round"`
+	Player1  string `json:"player1"`
+	Player2  string `json:"player2"`
+	Wins1    int    `json:"wins1"`
+	Wins2    int    `json:"wins2"`
+	Draws    int    `json:"draws"`
+	Reported bool   `json:"reported"`
+	Flagged  bool   `json:"flagged"`
+}
+
+type cardResponse struct {
+	Name            string `json:"name"`
+	Set             string `json:"set_code"`
+	CollectorNumber string `json:"collector_number"`
+	Foil            bool   `json:"foil"`
+	ScryfallURI     string `json:"scryfall_uri"`
+	ImageURL        string `json:"image_url"`
+	Count           int    `json:"count"`
+}
+
+type balanceResponse struct {
+	WildCards     map[repository.Rarity]int `json:"wild_cards"`
+	WildPacks     int                       `json:"wild_packs"`
+	VaultProgress int                       `json:"vault_progress"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func newStandingResponses(standings []league.Standing) []standingResponse {
+	responses := make([]standingResponse, 0, len(standings))
+	for _, standing := range standings {
+		responses = append(responses, standingResponse(standing))
+	}
+	return responses
+}
+
+func newPairingResponses(pairings []repository.Pairing) []pairingResponse {
+	responses := make([]pairingResponse, 0, len(pairings))
+	for _, pairing := range pairings {
+		responses = append(responses, pairingResponse{
+			Round:    pairing.Round,
+			Player1:  pairing.Player1,
+			Player2:  pairing.Player2,
+			Wins1:    pairing.Wins1,
+			Wins2:    pairing.Wins2,
+			Draws:    pairing.Draws,
+			Reported: pairing.Wins1 != 0 || pairing.Wins2 != 0 || pairing.Draws != 0,
+			Flagged:  pairing.Flagged,
+		})
+	}
+	return responses
+}
+
+func newCardResponses(cards []repository.Card) []cardResponse {
+	responses := make([]cardResponse, 0, len(cards))
+	for _, card := range cards {
+		responses = append(responses, cardResponse(card))
+	}
+	return responses
+}
+
+func newBalanceResponse(player repository.Player) balanceResponse {
+	wildCards := make(map[repository.Rarity]int, len(repository.Rarities))
+	for _, rarity := range repository.Rarities {
+		wildCards[rarity] = player.WildCards(rarity)
+	}
+
+	return balanceResponse{
+		WildCards:     wildCards,
+		WildPacks:     player.WildPacks,
+		VaultProgress: player.VaultProgress,
+	}
+}
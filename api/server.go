@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"progression/league"
+	"progression/repository"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// shutdownTimeout is the time requests in progress have to finish once the server is stopped.
+const shutdownTimeout = 5 * time.Second
+
+// Config configures the access to the API.
+type Config struct {
+	// Token has to be sent as a bearer token to the endpoints returning a player's pool or balance. Without a token, these endpoints are disabled.
+	Token string
+	// AllowedOrigins contains the origins, which browsers allow to call the API. "*" allows every origin.
+	AllowedOrigins []string
+}
+
+// Server serves a read-only JSON API of the league's state, e.g. for deck builders and stream overlays.
+type Server struct {
+	leagueManager *league.Manager
+	config        Config
+	handler       http.Handler
+}
+
+func New(leagueManager *league.Manager, config Config) *Server {
+	server := &Server{
+		leagueManager: leagueManager,
+		config:        config,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/standings", server.getStandings)
+	mux.HandleFunc("GET /api/rounds/{round}/pairings", server.getPairings)
+	mux.HandleFunc("GET /api/players/{userID}/pool", server.requireToken(server.getPool))
+	mux.HandleFunc("GET /api/players/{userID}/balance", server.requireToken(server.getBalance))
+	mux.HandleFunc("GET /api/sets", server.getSets)
+	mux.HandleFunc("GET /api/bans", server.getBans)
+	server.handler = server.withCORS(mux)
+
+	return server
+}
+
+// Handler returns the handler serving every endpoint of the API.
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// ListenAndServe serves the API on the given address until the context is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context, address string) error {
+	server := &http.Server{
+		Addr:              address,
+		Handler:           s.handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("Serving API...", "address", address)
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) getStandings(w http.ResponseWriter, _ *http.Request) {
+	standings, err := s.leagueManager.GetStandings()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newStandingResponses(standings))
+}
+
+func (s *Server) getPairings(w http.ResponseWriter, r *http.Request) {
+	round, err := strconv.Atoi(r.PathValue("round"))
+	if err != nil || round < 0 {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid round"})
+		return
+	}
+
+	pairings, err := s.leagueManager.GetPairings(round)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newPairingResponses(pairings))
+}
+
+func (s *Server) getPool(w http.ResponseWriter, r *http.Request) {
+	cards, err := s.leagueManager.GetPlayerCards(r.PathValue("userID"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newCardResponses(cards))
+}
+
+func (s *Server) getBalance(w http.ResponseWriter, r *http.Request) {
+	player, err := s.leagueManager.GetPlayerBalance(r.PathValue("userID"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newBalanceResponse(player))
+}
+
+func (s *Server) getSets(w http.ResponseWriter, _ *http.Request) {
+	sets, err := s.leagueManager.GetSets()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	setCodes := make([]string, 0, len(sets))
+	for _, set := range sets {
+		setCodes = append(setCodes, set.SetCode)
+	}
+	writeJSON(w, http.StatusOK, setCodes)
+}
+
+func (s *Server) getBans(w http.ResponseWriter, _ *http.Request) {
+	bans, err := s.leagueManager.GetBannedCards()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	cardNames := make([]string, 0, len(bans))
+	for _, ban := range bans {
+		cardNames = append(cardNames, ban.CardName)
+	}
+	writeJSON(w, http.StatusOK, cardNames)
+}
+
+// requireToken only calls the handler, if the request contains the configured bearer token.
+func (s *Server) requireToken(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if s.config.Token == "" || !found || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "a valid bearer token is required"})
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+// withCORS allows browsers to call the API from the configured origins and answers their preflight requests.
+func (s *Server) withCORS(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		allowed := origin != "" && (slices.Contains(s.config.AllowedOrigins, "*") || slices.Contains(s.config.AllowedOrigins, origin))
+		if allowed {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			if allowed {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Authorization")
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// writeError responds with the status code matching the error. Unexpected errors are logged instead of being exposed.
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNoActiveLeague):
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "no league is active"})
+	case errors.Is(err, repository.ErrPlayerNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "player not found"})
+	default:
+		slog.Error("API request failed", "error", err)
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "internal error"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		slog.Warn("failed to write API response", "error", err)
+	}
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"progression/league"
+	"progression/repository"
+	"progression/repository/repositorytest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "token"
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	dataStore := repositorytest.NewMemoryDataStore()
+	require.NoError(t, dataStore.StartLeague())
+	_, err := dataStore.NextRound()
+	require.NoError(t, err)
+	require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: "a", WildRares: 2, WildPacks: 1}))
+	require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: "b"}))
+	require.NoError(t, dataStore.StorePairings([]repository.Pairing{
+		{Round: 0, Player1: "a", Player2: "b", Wins1: 2, Wins2: 1},
+		{Round: 1, Player1: "b", Player2: "a", Flagged: true},
+	}))
+	dataStore.AddCards("a", repository.Card{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 2})
+	require.NoError(t, dataStore.UnlockSet("IKO", 0))
+	require.NoError(t, dataStore.UnlockSet("M21", 1))
+	require.NoError(t, dataStore.BanCard("Lurrus of the Dream-Den"))
+
+	leagueManager := league.NewLeagueManager(dataStore, nil, nil, league.DefaultConfig())
+	return New(leagueManager, Config{Token: testToken, AllowedOrigins: []string{"https://example.com"}})
+}
+
+func TestServer(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		token          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "standings",
+			path:           "/api/standings",
+			expectedStatus: http.StatusOK,
+			expectedBody: `[
+				{"player_id":"a","points":3,"match_wins":1,"match_losses":0,"match_draws":0,"game_wins":2,"game_losses":1,"game_draws":0,"dropped":false},
+				{"player_id":"b","points":0,"match_wins":0,"match_losses":1,"match_draws":0,"game_wins":1,"game_losses":2,"game_draws":0,"dropped":false}
+			]`,
+		},
+		{
+			name:           "pairings",
+			path:           "/api/rounds/1/pairings",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"round":1,"player1":"b","player2":"a","wins1":0,"wins2":0,"draws":0,"reported":false,"flagged":true}]`,
+		},
+		{
+			name:           "pairings_invalid_round",
+			path:           "/api/rounds/first/pairings",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid round"}`,
+		},
+		{
+			name:           "pool",
+			path:           "/api/players/a/pool",
+			token:          testToken,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"name":"Adaptive Shimmerer","set_code":"IKO","collector_number":"1","foil":false,"scryfall_uri":"","image_url":"","count":2}]`,
+		},
+		{
+			name:           "pool_without_token",
+			path:           "/api/players/a/pool",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"a valid bearer token is required"}`,
+		},
+		{
+			name:           "pool_invalid_token",
+			path:           "/api/players/a/pool",
+			token:          "guess",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"a valid bearer token is required"}`,
+		},
+		{
+			name:           "balance",
+			path:           "/api/players/a/balance",
+			token:          testToken,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"wild_cards":{"common":0,"uncommon":0,"rare":2,"mythic":0},"wild_packs":1,"vault_progress":0}`,
+		},
+		{
+			name:           "balance_unknown_player",
+			path:           "/api/players/c/balance",
+			token:          testToken,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"player not found"}`,
+		},
+		{
+			name:           "sets",
+			path:           "/api/sets",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `["IKO","M21"]`,
+		},
+		{
+			name:           "bans",
+			path:           "/api/bans",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `["Lurrus of the Dream-Den"]`,
+		},
+	}
+
+	server := newTestServer(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				request.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			recorder := httptest.NewRecorder()
+
+			server.Handler().ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestServer_CORS(t *testing.T) {
+	tests := []struct {
+		name            string
+		origin          string
+		method          string
+		expectedStatus  int
+		expectedOrigin  string
+		expectedMethods string
+	}{
+		{name: "allowed", origin: "https://example.com", method: http.MethodGet, expectedStatus: http.StatusOK, expectedOrigin: "https://example.com"},
+		{name: "not_allowed", origin: "https://evil.example", method: http.MethodGet, expectedStatus: http.StatusOK},
+		{name: "preflight", origin: "https://example.com", method: http.MethodOptions, expectedStatus: http.StatusNoContent,
+			expectedOrigin: "https://example.com", expectedMethods: "GET, OPTIONS"},
+		{name: "preflight_not_allowed", origin: "https://evil.example", method: http.MethodOptions, expectedStatus: http.StatusNoContent},
+	}
+
+	server := newTestServer(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, "/api/sets", nil)
+			request.Header.Set("Origin", tt.origin)
+			if tt.method == http.MethodOptions {
+				request.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			}
+			recorder := httptest.NewRecorder()
+
+			server.Handler().ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.Equal(t, tt.expectedOrigin, recorder.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tt.expectedMethods, recorder.Header().Get("Access-Control-Allow-Methods"))
+		})
+	}
+}
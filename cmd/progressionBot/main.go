@@ -1,46 +1,94 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
+	"progression/api"
 	"progression/discord"
+	"progression/envconfig"
 	"progression/league"
-	"progression/repository"
+	"progression/packGenerator"
+	"progression/scryfall"
+	"progression/web"
+	"progression/webhook"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 type config struct {
 	dcBotToken      string
+	dcGuildIDs      []string
+	dcRoles         discord.RoleCapabilities
+	dcChannelID     string
 	mbpgHostaddress string
-	pgDatabase      string
-	pgHostname      string
-	pgPassword      string
-	pgUsername      string
-	pgPort          int
+	leagueConfig    league.Config
+	webhookURLs     []string
+	webhookSecret   string
+	apiAddress      string
+	apiConfig       api.Config
+	webAddress      string
+	webShowPools    bool
 }
 
 func main() {
 	conf := parseEnv()
-	dataStore := repository.NewPostgresDataStore(
-		conf.pgHostname,
-		conf.pgPort,
-		conf.pgUsername,
-		conf.pgPassword,
-		conf.pgDatabase,
-	)
+	dataStore := envconfig.DataStore()
 	err := dataStore.Connect()
 	if err != nil {
 		slog.Error("failed to connect to datastore", "error", err)
 		return
 	}
-	leagueManager := league.NewLeagueManager(dataStore)
-	discordBot, err := discord.New(conf.dcBotToken, leagueManager)
+	scryfallClient, err := scryfall.NewClient()
+	if err != nil {
+		slog.Error("failed to create scryfall client", "error", err)
+		return
+	}
+	leagueManager := league.NewLeagueManager(dataStore, packGenerator.New(conf.mbpgHostaddress), scryfallClient, conf.leagueConfig)
+	discordBot, err := discord.New(conf.dcBotToken, leagueManager, conf.dcGuildIDs, conf.dcRoles, conf.dcChannelID)
 	if err != nil {
 		slog.Error("failed to create discord bot", "error", err)
 		return
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	leagueManager.Events().Subscribe(discordBot.Announce)
+	go league.NewScheduler(leagueManager).Run(ctx)
+
+	if len(conf.webhookURLs) != 0 {
+		dispatcher := webhook.NewDispatcher(dataStore, conf.webhookURLs, conf.webhookSecret)
+		leagueManager.Events().Subscribe(dispatcher.Handle)
+		go dispatcher.Run(ctx)
+	}
+
+	if conf.apiAddress != "" {
+		go func() {
+			err := api.New(leagueManager, conf.apiConfig).ListenAndServe(ctx, conf.apiAddress)
+			if err != nil {
+				slog.Error("API server ended", "error", err)
+			}
+		}()
+	}
+
+	if conf.webAddress != "" {
+		dashboard, err := web.New(leagueManager, web.Config{ShowPools: conf.webShowPools, PlayerName: discordBot.PlayerName})
+		if err != nil {
+			slog.Error("failed to create dashboard", "error", err)
+			return
+		}
+
+		go func() {
+			err := dashboard.ListenAndServe(ctx, conf.webAddress)
+			if err != nil {
+				slog.Error("dashboard server ended", "error", err)
+			}
+		}()
+	}
+
 	err = discordBot.Start()
 	slog.Info("discord bot ended", "error", err)
 }
@@ -48,18 +96,70 @@ func main() {
 func parseEnv() config {
 	conf := config{
 		dcBotToken:      os.Getenv("DC_BOT_TOKEN"),
+		dcChannelID:     os.Getenv("DC_ANNOUNCEMENTS_CHANNEL_ID"),
 		mbpgHostaddress: os.Getenv("MBPG_HOSTADDRESS"),
-		pgHostname:      os.Getenv("PG_HOSTNAME"),
-		pgDatabase:      os.Getenv("PG_DATABASE"),
-		pgUsername:      os.Getenv("PG_USERNAME"),
-		pgPassword:      os.Getenv("PG_PASSWORD"),
+		apiAddress:      os.Getenv("API_ADDRESS"),
+		webAddress:      os.Getenv("WEB_ADDRESS"),
+		leagueConfig:    envconfig.LeagueConfig(),
+		apiConfig: api.Config{
+			Token: os.Getenv("API_TOKEN"),
+		},
 	}
 
-	port, err := strconv.Atoi(os.Getenv("PG_PORT"))
-	if err != nil {
-		panic(fmt.Sprintf("PG_PORT environment variable not set to a valid value: %v", err))
+	if showPools := os.Getenv("WEB_SHOW_POOLS"); showPools != "" {
+		show, err := strconv.ParseBool(showPools)
+		if err != nil {
+			panic(fmt.Sprintf("WEB_SHOW_POOLS environment variable not set to a valid value: %v", err))
+		}
+		conf.webShowPools = show
+	}
+
+	if origins := os.Getenv("API_CORS_ORIGINS"); origins != "" {
+		for _, origin := range strings.Split(origins, ",") {
+			conf.apiConfig.AllowedOrigins = append(conf.apiConfig.AllowedOrigins, strings.TrimSpace(origin))
+		}
+	}
+
+	if guildIDs := os.Getenv("DC_GUILD_IDS"); guildIDs != "" {
+		for _, guildID := range strings.Split(guildIDs, ",") {
+			conf.dcGuildIDs = append(conf.dcGuildIDs, strings.TrimSpace(guildID))
+		}
+	}
+
+	if urls := os.Getenv("WEBHOOK_URLS"); urls != "" {
+		for _, url := range strings.Split(urls, ",") {
+			conf.webhookURLs = append(conf.webhookURLs, strings.TrimSpace(url))
+		}
+
+		conf.webhookSecret = os.Getenv("WEBHOOK_SECRET")
+		if conf.webhookSecret == "" {
+			panic("WEBHOOK_SECRET environment variable has to be set to sign the payloads sent to WEBHOOK_URLS")
+		}
+	}
+
+	if roles := os.Getenv("DC_ROLE_CAPABILITIES"); roles != "" {
+		conf.dcRoles = parseRoleCapabilities("DC_ROLE_CAPABILITIES", roles)
 	}
-	conf.pgPort = port
 
 	return conf
 }
+
+// parseRoleCapabilities parses a JSON object mapping guild IDs to role names and their capabilities, e.g. {"<guild_id>": {"League Organizer": ["start", "ban"]}}.
+func parseRoleCapabilities(name, value string) discord.RoleCapabilities {
+	var roles discord.RoleCapabilities
+	err := json.Unmarshal([]byte(value), &roles)
+	if err != nil {
+		panic(fmt.Sprintf("%s environment variable not set to a valid value: %v", name, err))
+	}
+
+	for _, guildRoles := range roles {
+		for role, capabilities := range guildRoles {
+			for _, capability := range capabilities {
+				if !slices.Contains(league.Capabilities, capability) {
+					panic(fmt.Sprintf("%s environment variable not set to a valid value: invalid capability %q for role %q", name, capability, role))
+				}
+			}
+		}
+	}
+	return roles
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"progression/repository"
+	"progression/snapshot"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// command is a subcommand of the CLI like "league start". run parses the arguments using the given flag set, which already contains -json.
+type command struct {
+	name        string
+	args        string
+	description string
+	run         func(ctx context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error)
+}
+
+func (c command) usage() string {
+	if c.args == "" {
+		return c.name
+	}
+	return c.name + " " + c.args
+}
+
+var commands = []command{
+	{name: "league start", args: "<set_code>", description: "Start a league with all players, who have joined so far, and open their packs of the set", run: leagueStart},
+	{name: "league end", description: "End the active league", run: leagueEnd},
+	{name: "round next", args: "<set_code>", description: "Start the next round, unlocking the set and giving every player wild packs", run: roundNext},
+	{name: "round rollback", description: "Roll back the current round, reverting its wild packs, cards, unlocked sets and pairings", run: roundRollback},
+	{name: "player list", description: "List the players and their balances", run: playerList},
+	{name: "player drop", args: "<user_id>", description: "Drop a player from the league, forfeiting their unreported match", run: playerDrop},
+	{name: "player grant wild_cards", args: "<user_id> <rarity> <count> <reason>", description: "Grant wild cards to a player", run: playerGrantWildCards},
+	{name: "player grant wild_packs", args: "<user_id> <count> <reason>", description: "Grant wild packs to a player", run: playerGrantWildPacks},
+	{name: "player grant card", args: "[-foil] <user_id> <set_code> <collector_number> <count> <reason>", description: "Grant copies of a card to a player", run: playerGrantCard},
+	{name: "pairing show", args: "[-round <round>]", description: "Show the pairings of the current or the given round", run: pairingShow},
+	{name: "pairing report", args: "<user_id> <wins> <losses> <draws>", description: "Report a player's match in the current round from their point of view", run: pairingReport},
+	{name: "ban list", description: "List the banned cards", run: banList},
+	{name: "ban add", args: "<card_name>", description: "Ban a card", run: banAdd},
+	{name: "ban remove", args: "<card_name>", description: "Unban a card", run: banRemove},
+	{name: "admin add", args: "<user_id>", description: "Give a user every capability", run: adminAdd},
+	{name: "pool export", args: "<user_id>", description: "Export a player's card pool as CSV", run: poolExport},
+	{name: "snapshot export", description: "Export the complete state of the league as JSON", run: snapshotExport},
+	{name: "snapshot import", args: "<file>", description: "Import a snapshot into the empty database, reading it from stdin if the file is -", run: snapshotImport},
+}
+
+func leagueStart(ctx context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	playerPacks, err := c.leagueManager.StartRound(ctx, cliActor, args[0], func(done, total int) {
+		fmt.Fprintf(c.stderr, "Opened the packs of %d/%d players\n", done, total)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	playerIDs := make([]string, 0, len(playerPacks))
+	for playerID := range playerPacks {
+		playerIDs = append(playerIDs, playerID)
+	}
+	slices.Sort(playerIDs)
+	return roundOutput{Round: 0, SetCode: strings.ToUpper(args[0]), PlayerIDs: playerIDs}, nil
+}
+
+func leagueEnd(ctx context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	_, err := parseArgs(flags, args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.leagueManager.EndLeague(ctx, cliActor)
+	if err != nil {
+		return nil, err
+	}
+	return messageOutput{Message: "The league has ended."}, nil
+}
+
+func roundNext(ctx context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	round, err := c.leagueManager.NextRound(ctx, cliActor, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := c.leagueManager.GetPlayers()
+	if err != nil {
+		return nil, err
+	}
+
+	playerIDs := make([]string, 0, len(players))
+	for _, player := range players {
+		playerIDs = append(playerIDs, player.Id)
+	}
+	return roundOutput{Round: round, SetCode: strings.ToUpper(args[0]), PlayerIDs: playerIDs}, nil
+}
+
+func roundRollback(ctx context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	_, err := parseArgs(flags, args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	round, err := c.leagueManager.RollbackRound(ctx, cliActor)
+	if err != nil {
+		return nil, err
+	}
+	return messageOutput{Message: fmt.Sprintf("The league has returned to round %d.", round)}, nil
+}
+
+func playerList(_ context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	_, err := parseArgs(flags, args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := c.leagueManager.GetPlayers()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(playersOutput, 0, len(players))
+	for _, player := range players {
+		result = append(result, newPlayerOutput(player))
+	}
+	return result, nil
+}
+
+func playerDrop(_ context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.leagueManager.DropPlayer(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return messageOutput{Message: fmt.Sprintf("%s has been dropped from the league.", args[0])}, nil
+}
+
+func playerGrantWildCards(ctx context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	args, err := parseArgs(flags, args, 4, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	rarity := repository.Rarity(args[1])
+	if !slices.Contains(repository.Rarities, rarity) {
+		return nil, fmt.Errorf("invalid rarity %q", args[1])
+	}
+
+	count, err := parseCount("count", args[2])
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := c.leagueManager.AdjustBalance(ctx, cliActor, args[0], repository.WildCardCurrency(rarity), count, args[3])
+	if err != nil {
+		return nil, err
+	}
+	return newPlayerOutput(player), nil
+}
+
+func playerGrantWildPacks(ctx context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	args, err := parseArgs(flags, args, 3, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := parseCount("count", args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := c.leagueManager.AdjustBalance(ctx, cliActor, args[0], repository.CurrencyWildPack, count, args[2])
+	if err != nil {
+		return nil, err
+	}
+	return newPlayerOutput(player), nil
+}
+
+func playerGrantCard(ctx context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	foil := flags.Bool("foil", false, "grant foil copies")
+	args, err := parseArgs(flags, args, 5, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := parseCount("count", args[3])
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := c.leagueManager.GrantCards(ctx, cliActor, args[0], args[1], args[2], *foil, count, args[4])
+	if err != nil {
+		return nil, err
+	}
+	card.Count = count
+	return cardsOutput{newCardOutput(card)}, nil
+}
+
+func pairingShow(_ context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	round := flags.Int("round", -1, "the round to show instead of the current one")
+	_, err := parseArgs(flags, args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if *round < 0 {
+		*round, err = c.leagueManager.GetRound()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pairings, err := c.leagueManager.GetPairings(*round)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(pairingsOutput, 0, len(pairings))
+	for _, pairing := range pairings {
+		result = append(result, newPairingOutput(pairing))
+	}
+	return result, nil
+}
+
+func pairingReport(ctx context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	args, err := parseArgs(flags, args, 4, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	var result [3]int
+	for idx, name := range []string{"wins", "losses", "draws"} {
+		result[idx], err = strconv.Atoi(args[idx+1])
+		if err != nil || result[idx] < 0 {
+			return nil, fmt.Errorf("invalid number of %s %q", name, args[idx+1])
+		}
+	}
+
+	err = c.leagueManager.ReportMatch(ctx, cliActor, args[0], result[0], result[1], result[2])
+	if err != nil {
+		return nil, err
+	}
+	return messageOutput{Message: fmt.Sprintf("Reported %d-%d-%d for %s.", result[0], result[1], result[2], args[0])}, nil
+}
+
+func banList(_ context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	_, err := parseArgs(flags, args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	bans, err := c.leagueManager.GetBannedCards()
+	if err != nil {
+		return nil, err
+	}
+
+	cardNames := make(listOutput, 0, len(bans))
+	for _, ban := range bans {
+		cardNames = append(cardNames, ban.CardName)
+	}
+	slices.Sort(cardNames)
+	return cardNames, nil
+}
+
+func banAdd(ctx context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.leagueManager.BanCard(ctx, cliActor, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return messageOutput{Message: fmt.Sprintf("%s has been banned.", args[0])}, nil
+}
+
+func banRemove(ctx context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.leagueManager.UnbanCard(ctx, cliActor, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return messageOutput{Message: fmt.Sprintf("%s has been unbanned.", args[0])}, nil
+}
+
+func adminAdd(_ context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	isAdmin, err := c.dataStore.IsAdmin(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAdmin {
+		err = c.dataStore.MakeAdmin(args[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return messageOutput{Message: fmt.Sprintf("%s is an admin.", args[0])}, nil
+}
+
+func poolExport(_ context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	cards, err := c.leagueManager.GetPlayerCards(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(cardsOutput, 0, len(cards))
+	for _, card := range cards {
+		result = append(result, newCardOutput(card))
+	}
+	return result, nil
+}
+
+// parseCount parses a positive number.
+func parseCount(name, value string) (int, error) {
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return count, nil
+}
+
+func snapshotExport(_ context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	_, err := parseArgs(flags, args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	exported, err := snapshot.Export(c.dataStore)
+	if err != nil {
+		return nil, err
+	}
+	return snapshotOutput(exported), nil
+}
+
+func snapshotImport(_ context.Context, c *cli, flags *flag.FlagSet, args []string) (output, error) {
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := c.stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	imported, err := snapshot.Read(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	err = snapshot.Import(c.dataStore, imported)
+	if err != nil {
+		return nil, err
+	}
+	return messageOutput{Message: fmt.Sprintf("Imported %d leagues and %d players.", len(imported.Leagues), len(imported.Players))}, nil
+}
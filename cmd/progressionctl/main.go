@@ -0,0 +1,177 @@
+// Command progressionctl operates the league from the command line, e.g. to make a user an admin or fix a player's pool without discord.
+// It uses the same environment variables as the bot.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"progression/envconfig"
+	"progression/league"
+	"progression/packGenerator"
+	"progression/repository"
+	"progression/scryfall"
+	"strings"
+	"text/tabwriter"
+)
+
+// cliActor is recorded as the user performing every change made with the CLI, e.g. in the ledger.
+const cliActor = "progressionctl"
+
+// errUsage is returned when a command is called with invalid arguments.
+var errUsage = errors.New("invalid usage")
+
+// cli runs the commands. Every command is allowed to perform any administrative action.
+type cli struct {
+	leagueManager *league.Manager
+	dataStore     repository.DataStore
+	stdin         io.Reader
+	stdout        io.Writer
+	stderr        io.Writer
+	// json is set by the -json flag, which every command accepts.
+	json bool
+}
+
+func main() {
+	dataStore := envconfig.DataStore()
+	err := dataStore.Connect()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to connect to datastore:", err)
+		os.Exit(1)
+	}
+
+	scryfallClient, err := scryfall.NewClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to create scryfall client:", err)
+		os.Exit(1)
+	}
+
+	c := &cli{
+		leagueManager: league.NewLeagueManager(dataStore, packGenerator.New(os.Getenv("MBPG_HOSTADDRESS")), scryfallClient, envconfig.LeagueConfig()),
+		dataStore:     dataStore,
+		stdin:         os.Stdin,
+		stdout:        os.Stdout,
+		stderr:        os.Stderr,
+	}
+
+	err = c.run(context.Background(), os.Args[1:])
+	switch {
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// run finds the command matching the arguments, runs it and prints its output.
+func (c *cli) run(ctx context.Context, args []string) error {
+	cmd, args, found := findCommand(args)
+	if !found {
+		c.printUsage()
+		return errUsage
+	}
+
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.BoolVar(&c.json, "json", false, "print the result as JSON")
+	flags.Usage = func() {
+		fmt.Fprintf(c.stderr, "usage: progressionctl %s\n\n%s\n\n", cmd.usage(), cmd.description)
+		flags.PrintDefaults()
+	}
+	result, err := cmd.run(league.WithCapabilities(ctx, league.Capabilities), c, flags, args)
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		encoder := json.NewEncoder(c.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	writer := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	err = result.writeText(writer)
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// findCommand returns the command named by the first arguments and the remaining arguments.
+func findCommand(args []string) (command, []string, bool) {
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == cmd.name {
+			return cmd, args[len(words):], true
+		}
+	}
+	return command{}, nil, false
+}
+
+func (c *cli) printUsage() {
+	fmt.Fprintf(c.stderr, "usage: progressionctl <command> [arguments] [-json]\n\ncommands:\n")
+	writer := tabwriter.NewWriter(c.stderr, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(writer, "  %s\t%s\n", cmd.usage(), cmd.description)
+	}
+	_ = writer.Flush()
+}
+
+// parseArgs parses the flags and returns the positional arguments. Flags can be given before, between or after the arguments.
+// It prints the command's usage and returns errUsage, unless the flags are valid and the number of arguments lies between min and max.
+func parseArgs(flags *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	flagArgs, positional := splitFlags(flags, args)
+	err := flags.Parse(flagArgs)
+	if err != nil {
+		// the flag package has already printed the usage
+		return nil, errUsage
+	}
+
+	if len(positional) < min || len(positional) > max {
+		flags.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+// splitFlags separates the flags including their values from the positional arguments,
+// since the flag package stops parsing at the first positional argument. Everything after "--" is positional.
+func splitFlags(flags *flag.FlagSet, args []string) ([]string, []string) {
+	var flagArgs, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+
+		// a single dash is an argument, e.g. for reading from stdin
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+
+		flagArgs = append(flagArgs, arg)
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		defined := flags.Lookup(name)
+		if hasValue || defined == nil || isBoolFlag(defined) || i+1 == len(args) {
+			continue
+		}
+
+		// the value of a non-boolean flag may be given as the next argument
+		i++
+		flagArgs = append(flagArgs, args[i])
+	}
+	return flagArgs, positional
+}
+
+// isBoolFlag returns whether the flag can be given without a value.
+func isBoolFlag(f *flag.Flag) bool {
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
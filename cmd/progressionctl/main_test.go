@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"progression/league"
+	"progression/repository"
+	"progression/repository/repositorytest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDataStore returns a datastore with a league in its second round, the players "a" and "b" and a card in the card pool of "a".
+func newTestDataStore(t *testing.T) *repositorytest.MemoryDataStore {
+	t.Helper()
+
+	dataStore := repositorytest.NewMemoryDataStore()
+	require.NoError(t, dataStore.StartLeague())
+	_, err := dataStore.NextRound()
+	require.NoError(t, err)
+	require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: "a", WildRares: 2, WildPacks: 1}))
+	require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: "b"}))
+	require.NoError(t, dataStore.StorePairings([]repository.Pairing{
+		{Round: 0, Player1: "a", Player2: "b", Wins1: 2, Wins2: 1},
+		{Round: 1, Player1: "b", Player2: "a", Flagged: true},
+	}))
+	dataStore.AddCards("a", repository.Card{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 2})
+	return dataStore
+}
+
+func newTestCLI(dataStore repository.DataStore) (*cli, *bytes.Buffer, *bytes.Buffer) {
+	var stdout, stderr bytes.Buffer
+	return &cli{
+		leagueManager: league.NewLeagueManager(dataStore, nil, nil, league.DefaultConfig()),
+		dataStore:     dataStore,
+		stdout:        &stdout,
+		stderr:        &stderr,
+	}, &stdout, &stderr
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     string
+		expected string
+	}{
+		{
+			name:     "player_list",
+			args:     "player list",
+			expected: "ID  COMMON  UNCOMMON  RARE  MYTHIC  PACKS  VAULT\na   0       0         2     0       1      0\nb   0       0         0     0       0      0\n",
+		},
+		{
+			name: "player_list_json",
+			args: "player list -json",
+			expected: `[
+  {
+    "id": "a",
+    "wild_cards": {
+      "common": 0,
+      "mythic": 0,
+      "rare": 2,
+      "uncommon": 0
+    },
+    "wild_packs": 1,
+    "vault_progress": 0
+  },
+  {
+    "id": "b",
+    "wild_cards": {
+      "common": 0,
+      "mythic": 0,
+      "rare": 0,
+      "uncommon": 0
+    },
+    "wild_packs": 0,
+    "vault_progress": 0
+  }
+]
+`,
+		},
+		{
+			name:     "pairing_show",
+			args:     "pairing show",
+			expected: "ROUND  PLAYER 1  PLAYER 2  RESULT\n1      b         a         flagged\n",
+		},
+		{
+			name:     "pairing_show_round",
+			args:     "pairing show -round 0",
+			expected: "ROUND  PLAYER 1  PLAYER 2  RESULT\n0      a         b         2-1-0\n",
+		},
+		{
+			name:     "pool_export",
+			args:     "pool export a",
+			expected: "count,name,set,collector_number,foil,scryfall_uri,image_url\n2,Adaptive Shimmerer,IKO,1,false,,\n",
+		},
+		{
+			name:     "admin_add",
+			args:     "admin add a",
+			expected: "a is an admin.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, stdout, _ := newTestCLI(newTestDataStore(t))
+
+			err := c.run(context.Background(), strings.Fields(tt.args))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, stdout.String())
+		})
+	}
+}
+
+func TestRun_admin_add(t *testing.T) {
+	dataStore := newTestDataStore(t)
+	require.NoError(t, dataStore.MakeAdmin("a"))
+	c, _, _ := newTestCLI(dataStore)
+
+	err := c.run(context.Background(), []string{"admin", "add", "a"})
+	assert.NoError(t, err)
+	err = c.run(context.Background(), []string{"admin", "add", "b"})
+	assert.NoError(t, err)
+	admins, err := dataStore.GetAdmins()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, admins, "expected existing admins to be kept once")
+}
+
+func TestRun_usage(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     string
+		expected string
+	}{
+		{name: "no_command", args: "", expected: "usage: progressionctl <command>"},
+		{name: "unknown_command", args: "league pause", expected: "usage: progressionctl <command>"},
+		{name: "missing_argument", args: "player drop", expected: "usage: progressionctl player drop <user_id>"},
+		{name: "too_many_arguments", args: "league end now", expected: "usage: progressionctl league end"},
+		{name: "unknown_flag", args: "player list -csv", expected: "flag provided but not defined: -csv"},
+		{name: "unknown_flag_after_arguments", args: "player drop a -csv", expected: "flag provided but not defined: -csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, stdout, stderr := newTestCLI(newTestDataStore(t))
+
+			err := c.run(context.Background(), strings.Fields(tt.args))
+			assert.ErrorIs(t, err, errUsage)
+			assert.Contains(t, stderr.String(), tt.expected)
+			assert.Empty(t, stdout.String())
+		})
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     string
+		expected []string
+		json     bool
+		foil     bool
+		round    int
+	}{
+		{name: "flags_first", args: "-json -foil a IKO", expected: []string{"a", "IKO"}, json: true, foil: true, round: -1},
+		{name: "flags_between", args: "a -json IKO", expected: []string{"a", "IKO"}, json: true, round: -1},
+		{name: "flags_last", args: "a IKO -round 2 -json", expected: []string{"a", "IKO"}, json: true, round: 2},
+		{name: "flag_value", args: "a -round=2 IKO", expected: []string{"a", "IKO"}, round: 2},
+		{name: "stdin", args: "- -json", expected: []string{"-"}, json: true, round: -1},
+		{name: "terminator", args: "a -- -json", expected: []string{"a", "-json"}, round: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			json := flags.Bool("json", false, "")
+			foil := flags.Bool("foil", false, "")
+			round := flags.Int("round", -1, "")
+
+			args, err := parseArgs(flags, strings.Fields(tt.args), 0, 2)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+			assert.Equal(t, tt.json, *json)
+			assert.Equal(t, tt.foil, *foil)
+			assert.Equal(t, tt.round, *round)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"progression/league"
+	"progression/repository"
+	"progression/snapshot"
+	"strings"
+)
+
+// output is the result of a command. It is printed as JSON with -json and as text otherwise.
+// The text is written to a tabwriter, so tab separated columns are aligned. Write errors only surface, once the tabwriter is flushed.
+type output interface {
+	writeText(w io.Writer) error
+}
+
+type messageOutput struct {
+	Message string `json:"message"`
+}
+
+type roundOutput struct {
+	Round     int      `json:"round"`
+	SetCode   string   `json:"set_code"`
+	PlayerIDs []string `json:"player_ids"`
+}
+
+type playerOutput struct {
+	ID            string                    `json:"id"`
+	WildCards     map[repository.Rarity]int `json:"wild_cards"`
+	WildPacks     int                       `json:"wild_packs"`
+	VaultProgress int                       `json:"vault_progress"`
+}
+
+type playersOutput []playerOutput
+
+type pairingOutput struct {
+	Round    int    `json:"round"`
+	Player1  string `json:"player1"`
+	Player2  string `json:"player2"`
+	Wins1    int    `json:"wins1"`
+	Wins2    int    `json:"wins2"`
+	Draws    int    `json:"draws"`
+	Reported bool   `json:"reported"`
+	Flagged  bool   `json:"flagged"`
+}
+
+type pairingsOutput []pairingOutput
+
+type cardOutput struct {
+	Name            string `json:"name"`
+	Set             string `json:"set_code"`
+	CollectorNumber string `json:"collector_number"`
+	Foil            bool   `json:"foil"`
+	ScryfallURI     string `json:"scryfall_uri"`
+	ImageURL        string `json:"image_url"`
+	Count           int    `json:"count"`
+}
+
+type cardsOutput []cardOutput
+
+type listOutput []string
+
+// snapshotOutput is printed in the snapshot format, regardless of -json.
+type snapshotOutput snapshot.Snapshot
+
+func newPlayerOutput(player repository.Player) playerOutput {
+	wildCards := make(map[repository.Rarity]int, len(repository.Rarities))
+	for _, rarity := range repository.Rarities {
+		wildCards[rarity] = player.WildCards(rarity)
+	}
+
+	return playerOutput{
+		ID:            player.Id,
+		WildCards:     wildCards,
+		WildPacks:     player.WildPacks,
+		VaultProgress: player.VaultProgress,
+	}
+}
+
+func newPairingOutput(pairing repository.Pairing) pairingOutput {
+	return pairingOutput{
+		Round:    pairing.Round,
+		Player1:  pairing.Player1,
+		Player2:  pairing.Player2,
+		Wins1:    pairing.Wins1,
+		Wins2:    pairing.Wins2,
+		Draws:    pairing.Draws,
+		Reported: pairing.Wins1 != 0 || pairing.Wins2 != 0 || pairing.Draws != 0,
+		Flagged:  pairing.Flagged,
+	}
+}
+
+func newCardOutput(card repository.Card) cardOutput {
+	return cardOutput(card)
+}
+
+func (o messageOutput) writeText(w io.Writer) error {
+	_, err := fmt.Fprintln(w, o.Message)
+	return err
+}
+
+func (o roundOutput) writeText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "Round %d started with %d players. %s is unlocked.\n", o.Round, len(o.PlayerIDs), o.SetCode)
+	return err
+}
+
+func (o playerOutput) writeText(w io.Writer) error {
+	return playersOutput{o}.writeText(w)
+}
+
+func (o playersOutput) writeText(w io.Writer) error {
+	header := []string{"ID"}
+	for _, rarity := range repository.Rarities {
+		header = append(header, strings.ToUpper(string(rarity)))
+	}
+	fmt.Fprintln(w, strings.Join(append(header, "PACKS", "VAULT"), "\t"))
+
+	for _, player := range o {
+		fmt.Fprint(w, player.ID)
+		for _, rarity := range repository.Rarities {
+			fmt.Fprintf(w, "\t%d", player.WildCards[rarity])
+		}
+		fmt.Fprintf(w, "\t%d\t%d\n", player.WildPacks, player.VaultProgress)
+	}
+	return nil
+}
+
+func (o pairingsOutput) writeText(w io.Writer) error {
+	fmt.Fprintln(w, "ROUND\tPLAYER 1\tPLAYER 2\tRESULT")
+	for _, pairing := range o {
+		result := "pending"
+		switch {
+		case pairing.Reported:
+			result = fmt.Sprintf("%d-%d-%d", pairing.Wins1, pairing.Wins2, pairing.Draws)
+		case pairing.Flagged:
+			result = "flagged"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", pairing.Round, pairing.Player1, pairing.Player2, result)
+	}
+	return nil
+}
+
+// writeText writes the cards in the CSV format of discord's pool export.
+func (o cardsOutput) writeText(w io.Writer) error {
+	cards := make([]repository.Card, 0, len(o))
+	for _, card := range o {
+		cards = append(cards, repository.Card(card))
+	}
+
+	csv, err := league.ExportCardList(cards)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(csv)
+	return err
+}
+
+func (o listOutput) writeText(w io.Writer) error {
+	for _, entry := range o {
+		fmt.Fprintln(w, entry)
+	}
+	return nil
+}
+
+func (o snapshotOutput) writeText(w io.Writer) error {
+	return snapshot.Write(w, snapshot.Snapshot(o))
+}
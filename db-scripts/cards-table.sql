@@ -2,7 +2,10 @@ CREATE TABLE player_card_pool (
     id                  varchar(36)     NOT NULL,
     name                varchar(255)    NOT NULL,
     set_code            varchar(4)      NOT NULL,
-    collector_number    int             NOT NULL,
+    collector_number    varchar(16)     NOT NULL,
+    foil                boolean         NOT NULL DEFAULT false,
+    scryfall_uri        varchar(255)    NOT NULL DEFAULT '',
+    image_url           varchar(255)    NOT NULL DEFAULT '',
     count               int             NOT NULL,
-    PRIMARY KEY (id, set_code, collector_number)
+    PRIMARY KEY (id, set_code, collector_number, foil)
 );
\ No newline at end of file
@@ -1,5 +1,8 @@
-CREATE TABLE league (
-    round       int         NOT NULL,
-    active      bool        NOT NULL,
-    started_at  timestamptz NULL
-);
\ No newline at end of file
+CREATE TABLE league (
+    round                   int         NOT NULL,
+    active                  bool        NOT NULL,
+    started_at              timestamptz NULL,
+    round_duration_seconds  int         NOT NULL DEFAULT 0,
+    round_deadline          timestamptz NULL,
+    reminded_at             timestamptz NULL
+);
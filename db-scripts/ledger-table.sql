@@ -0,0 +1,17 @@
+CREATE TABLE ledger (
+    id          serial      PRIMARY KEY,
+    player_id   varchar(36) NOT NULL,
+    currency    varchar(16) NOT NULL,
+    amount      int         NOT NULL,
+    reason      varchar(32) NOT NULL,
+    round       int         NOT NULL,
+    actor       varchar(36) NOT NULL,
+    note        text        NOT NULL DEFAULT '',
+    created_at  timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ledger_player_idx ON ledger (player_id);
+
+-- the ledger is append-only
+CREATE RULE ledger_no_update AS ON UPDATE TO ledger DO INSTEAD NOTHING;
+CREATE RULE ledger_no_delete AS ON DELETE TO ledger DO INSTEAD NOTHING;
\ No newline at end of file
@@ -1,7 +1,11 @@
 CREATE TABLE player (
-      id                varchar(36) NOT NULL,
-      wild_card_count   int         NOT NULL,
-      wild_pack_count   int         NOT NULL,
-      dropped           boolean     NOT NULL DEFAULT false,
+      id                    varchar(36) NOT NULL,
+      wild_common_count     int         NOT NULL DEFAULT 0,
+      wild_uncommon_count   int         NOT NULL DEFAULT 0,
+      wild_rare_count       int         NOT NULL DEFAULT 0,
+      wild_mythic_count     int         NOT NULL DEFAULT 0,
+      wild_pack_count       int         NOT NULL,
+      vault_progress        int         NOT NULL DEFAULT 0,
+      dropped               boolean     NOT NULL DEFAULT false,
       PRIMARY KEY (id)
 );
\ No newline at end of file
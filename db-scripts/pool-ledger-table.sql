@@ -0,0 +1,22 @@
+CREATE TABLE pool_ledger (
+    id                  serial          PRIMARY KEY,
+    player_id           varchar(36)     NOT NULL,
+    name                varchar(255)    NOT NULL,
+    set_code            varchar(4)      NOT NULL,
+    collector_number    varchar(16)     NOT NULL,
+    foil                boolean         NOT NULL DEFAULT false,
+    scryfall_uri        varchar(255)    NOT NULL DEFAULT '',
+    image_url           varchar(255)    NOT NULL DEFAULT '',
+    count               int             NOT NULL,
+    reason              varchar(32)     NOT NULL,
+    round               int             NOT NULL,
+    actor               varchar(36)     NOT NULL,
+    created_at          timestamptz     NOT NULL DEFAULT now()
+);
+
+CREATE INDEX pool_ledger_player_idx ON pool_ledger (player_id);
+CREATE INDEX pool_ledger_round_idx ON pool_ledger (round);
+
+-- the pool ledger is append-only
+CREATE RULE pool_ledger_no_update AS ON UPDATE TO pool_ledger DO INSTEAD NOTHING;
+CREATE RULE pool_ledger_no_delete AS ON DELETE TO pool_ledger DO INSTEAD NOTHING;
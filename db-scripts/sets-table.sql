@@ -1,3 +1,5 @@
 CREATE TABLE sets (
-    set_code    varchar(4)  PRIMARY KEY
+    set_code    varchar(4)  PRIMARY KEY,
+    unlocked_at timestamptz NOT NULL DEFAULT now(),
+    round       int         NOT NULL DEFAULT 0
 );
@@ -0,0 +1,22 @@
+CREATE TABLE trade (
+    id          serial      PRIMARY KEY,
+    round       int         NOT NULL,
+    proposer    varchar(36) NOT NULL,
+    recipient   varchar(36) NOT NULL,
+    status      varchar(16) NOT NULL,
+    created_at  timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE trade_item (
+    trade_id            int             NOT NULL REFERENCES trade (id),
+    from_player         varchar(36)     NOT NULL,
+    currency            varchar(16)     NOT NULL DEFAULT '',
+    name                varchar(255)    NOT NULL DEFAULT '',
+    set_code            varchar(4)      NOT NULL DEFAULT '',
+    collector_number    varchar(16)     NOT NULL DEFAULT '',
+    foil                boolean         NOT NULL DEFAULT false,
+    rarity              varchar(16)     NOT NULL DEFAULT '',
+    count               int             NOT NULL
+);
+
+CREATE INDEX trade_item_trade_idx ON trade_item (trade_id);
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE webhook_delivery (
+    id              serial          PRIMARY KEY,
+    url             text            NOT NULL,
+    event           varchar(32)     NOT NULL,
+    payload         text            NOT NULL,
+    status          varchar(16)     NOT NULL,
+    attempts        int             NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz     NOT NULL DEFAULT now(),
+    response_code   int             NOT NULL DEFAULT 0,
+    last_error      text            NOT NULL DEFAULT '',
+    created_at      timestamptz     NOT NULL DEFAULT now(),
+    delivered_at    timestamptz     NULL
+);
+
+CREATE INDEX webhook_delivery_due_idx ON webhook_delivery (status, next_attempt_at);
@@ -0,0 +1,87 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"progression/league"
+	"progression/repository"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// minCount is the lowest number of wild cards, packs or cards an admin can grant or revoke at once.
+var minCount = 1.0
+
+func (b *Bot) AdminCommand(s Responder, i *discordgo.InteractionCreate) error {
+	adminID := interactionUserID(i)
+	group := i.ApplicationCommandData().Options[0]
+	subCommand := group.Options[0]
+
+	sign := 1
+	if group.Name == "revoke" {
+		sign = -1
+	}
+
+	userID := subCommand.GetOption("user").UserValue(nil).ID
+	count := int(subCommand.GetOption("count").IntValue())
+	reason := subCommand.GetOption("reason").StringValue()
+
+	var message string
+	switch subCommand.Name {
+	case "wild_cards", "wild_packs":
+		currency := repository.CurrencyWildPack
+		if subCommand.Name == "wild_cards" {
+			currency = repository.WildCardCurrency(repository.Rarity(subCommand.GetOption("rarity").StringValue()))
+		}
+
+		_, err := b.leagueManager.AdjustBalance(b.authorizedContext(s, i), adminID, userID, currency, sign*count, reason)
+		if err != nil {
+			message = formatAdjustmentError(err)
+		} else {
+			message = fmt.Sprintf("Adjusted the %s balance of <@%s> by %+d.", currency, userID, sign*count)
+		}
+	case "card":
+		setCode := subCommand.GetOption("set_code").StringValue()
+		collectorNumber := subCommand.GetOption("collector_number").StringValue()
+		foil := false
+		if option := subCommand.GetOption("foil"); option != nil {
+			foil = option.BoolValue()
+		}
+
+		var card repository.Card
+		var err error
+		if sign > 0 {
+			card, err = b.leagueManager.GrantCards(b.authorizedContext(s, i), adminID, userID, setCode, collectorNumber, foil, count, reason)
+		} else {
+			card, err = b.leagueManager.RevokeCards(b.authorizedContext(s, i), adminID, userID, setCode, collectorNumber, foil, count, reason)
+		}
+		if err != nil {
+			message = formatAdjustmentError(err)
+		} else {
+			message = fmt.Sprintf("Adjusted the card pool of <@%s> by %+d %s (%s #%s).", userID, sign*count, card.Name, card.Set, card.CollectorNumber)
+		}
+	default:
+		message = fmt.Sprintf("Unknown sub command %q.", subCommand.Name)
+	}
+
+	return b.SendMessage(s, i, message)
+}
+
+func formatAdjustmentError(err error) string {
+	switch {
+	case errors.Is(err, league.ErrPlayerNotAdmin):
+		return "You are not an admin."
+	case errors.Is(err, repository.ErrPlayerNotFound):
+		return "The given user is not part of the current league."
+	case errors.Is(err, league.ErrMissingReason):
+		return "You have to give a reason."
+	case errors.Is(err, league.ErrInsufficientWildCards), errors.Is(err, league.ErrInsufficientWildPacks):
+		return "The player doesn't have that many left."
+	case errors.Is(err, repository.ErrCardNotInPool):
+		return "The player doesn't own that many copies of the card."
+	case errors.Is(err, league.ErrCardNotFound):
+		return "The given card does not exist."
+	default:
+		return "Error adjusting the player: " + err.Error()
+	}
+}
@@ -0,0 +1,85 @@
+package discord
+
+import (
+	"fmt"
+	"log/slog"
+	"progression/league"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Announce posts the league's event in the announcement channel. Subscribe it to the league's events to keep everyone up to date.
+// Players are reminded of unreported matches in a direct message in addition.
+func (b *Bot) Announce(event league.Event) {
+	b.announce(sessionResponder{b.session}, event)
+}
+
+func (b *Bot) announce(s Responder, event league.Event) {
+	if reminder, ok := event.(league.DeadlineReminder); ok {
+		b.remindPlayers(s, reminder)
+		return
+	}
+
+	message := formatAnnouncement(event)
+	if message == "" {
+		return
+	}
+
+	b.sendAnnouncement(s, message)
+}
+
+// formatAnnouncement describes the event for the announcement channel. It returns an empty string for events, which aren't announced.
+func formatAnnouncement(event league.Event) string {
+	switch event := event.(type) {
+	case league.PlayerJoined:
+		return fmt.Sprintf("<@%s> has joined the league.", event.UserID)
+	case league.PlayerDropped:
+		return fmt.Sprintf("<@%s> has dropped from the league.", event.UserID)
+	case league.MatchReported:
+		pairing := event.Pairing
+		return fmt.Sprintf("<@%s> vs <@%s> ended %d-%d-%d.", pairing.Player1, pairing.Player2, pairing.Wins1, pairing.Wins2, pairing.Draws)
+	case league.RoundStarted:
+		message := fmt.Sprintf("A new round has started with %d players! Check /pool for your cards.", len(event.PlayerIDs))
+		if event.Deadline != nil {
+			message += fmt.Sprintf(" Report your matches by %s.", formatTimestamp(*event.Deadline))
+		}
+		return message
+	case league.RoundEnded:
+		return "All matches of the round have been reported. Every player has received their rewards, check /balance."
+	case league.RoundRolledBack:
+		message := "The last round has been rolled back, the previous round continues."
+		if len(event.SetCodes) != 0 {
+			message += fmt.Sprintf(" %s can no longer be redeemed.", strings.Join(event.SetCodes, ", "))
+		}
+		return message + " Check /balance and /pool for your reverted wild packs and cards."
+	case league.SetUnlocked:
+		return fmt.Sprintf("%s is now available to redeem wild cards and packs for.", event.SetCode)
+	case league.CardBanned:
+		return fmt.Sprintf("%s has been banned.", event.CardName)
+	case league.CardUnbanned:
+		return fmt.Sprintf("%s has been unbanned.", event.CardName)
+	case league.TradeAccepted:
+		return fmt.Sprintf("<@%s> and <@%s> have completed a trade.", event.Trade.Proposer, event.Trade.Recipient)
+	case league.DeadlinePassed:
+		return formatDeadlinePassed(event)
+	default:
+		return ""
+	}
+}
+
+// sendAnnouncement posts the message in the announcement channel. It does nothing, if no announcement channel is configured.
+func (b *Bot) sendAnnouncement(s Responder, message string) {
+	if b.announcementChannelID == "" {
+		return
+	}
+
+	_, err := s.ChannelMessageSendComplex(b.announcementChannelID, &discordgo.MessageSend{
+		Content: message,
+		// Announcements mention players to show their names, but must not notify them for every event.
+		AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+	})
+	if err != nil {
+		slog.Warn("failed to post announcement", "error", err, "channel", b.announcementChannelID)
+	}
+}
@@ -0,0 +1,44 @@
+package discord
+
+import (
+	"progression/league"
+	"progression/repository"
+	"progression/repository/repositorytest"
+	"testing"
+	"time"
+)
+
+func TestAnnounce(t *testing.T) {
+	deadline := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	pairings := []repository.Pairing{
+		{Player1: testUserID, Player2: "opponent"},
+		{Player1: "slow", Player2: "slower"},
+	}
+
+	tests := []struct {
+		name  string
+		event league.Event
+	}{
+		{name: "player_joined", event: league.PlayerJoined{UserID: testUserID}},
+		{name: "match_reported", event: league.MatchReported{Pairing: repository.Pairing{Player1: testUserID, Player2: "opponent", Wins1: 2, Wins2: 1}, ReporterID: testUserID}},
+		{name: "round_started", event: league.RoundStarted{SetCode: "IKO", PlayerIDs: []string{testUserID, "opponent"}, Deadline: &deadline}},
+		{name: "round_ended", event: league.RoundEnded{}},
+		{name: "round_rolled_back", event: league.RoundRolledBack{Round: 1, SetCodes: []string{"M21"}}},
+		{name: "set_unlocked", event: league.SetUnlocked{SetCode: "IKO"}},
+		{name: "card_banned", event: league.CardBanned{CardName: "Lurrus of the Dream-Den"}},
+		{name: "remind_players", event: league.DeadlineReminder{Deadline: deadline, Pairings: pairings[:1]}},
+		{name: "deadline_passed_draw", event: league.DeadlinePassed{Policy: league.DeadlinePolicyDraw, Pairings: pairings}},
+		{name: "deadline_passed_flag", event: league.DeadlinePassed{Policy: league.DeadlinePolicyFlag, Pairings: pairings}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newTestBot(t, repositorytest.NewMemoryDataStore(), nil)
+			responder := &recordingResponder{}
+
+			bot.announce(responder, tt.event)
+
+			assertGolden(t, "announce_"+tt.name, responder.calls)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxChoices is the maximum number of choices discord accepts in an autocomplete response.
+const maxChoices = 25
+
+// autocompleteTimeout leaves some headroom to discord's deadline of 3 seconds for responding to an interaction.
+const autocompleteTimeout = 2 * time.Second
+
+func (b *Bot) BanAutocomplete(s Responder, i *discordgo.InteractionCreate) error {
+	option := focusedOption(i.ApplicationCommandData().Options)
+	if option == nil {
+		return b.SendChoices(s, i, nil)
+	}
+
+	return b.sendCardNameChoices(s, i, option.StringValue())
+}
+
+func (b *Bot) UnbanAutocomplete(s Responder, i *discordgo.InteractionCreate) error {
+	option := focusedOption(i.ApplicationCommandData().Options)
+	if option == nil {
+		return b.SendChoices(s, i, nil)
+	}
+
+	bans, err := b.leagueManager.GetBannedCards()
+	if err != nil {
+		return errors.Join(err, b.SendChoices(s, i, nil))
+	}
+
+	cardNames := make([]string, len(bans))
+	for idx, ban := range bans {
+		cardNames[idx] = ban.CardName
+	}
+
+	return b.SendChoices(s, i, generateChoices(cardNames, option.StringValue()))
+}
+
+func (b *Bot) RedeemAutocomplete(s Responder, i *discordgo.InteractionCreate) error {
+	option := focusedOption(i.ApplicationCommandData().Options)
+	if option == nil {
+		return b.SendChoices(s, i, nil)
+	}
+
+	if option.Name == "card_name" {
+		return b.sendCardNameChoices(s, i, option.StringValue())
+	}
+
+	return b.sendSetCodeChoices(s, i, option.StringValue())
+}
+
+func (b *Bot) StartAutocomplete(s Responder, i *discordgo.InteractionCreate) error {
+	option := focusedOption(i.ApplicationCommandData().Options)
+	if option == nil {
+		return b.SendChoices(s, i, nil)
+	}
+
+	return b.sendSetCodeChoices(s, i, option.StringValue())
+}
+
+// sendSetCodeChoices responds with the codes of the unlocked sets, which contain the given partial set code.
+func (b *Bot) sendSetCodeChoices(s Responder, i *discordgo.InteractionCreate, partialSetCode string) error {
+	sets, err := b.leagueManager.GetSets()
+	if err != nil {
+		return errors.Join(err, b.SendChoices(s, i, nil))
+	}
+
+	setCodes := make([]string, len(sets))
+	for idx, set := range sets {
+		setCodes[idx] = set.SetCode
+	}
+
+	return b.SendChoices(s, i, generateChoices(setCodes, partialSetCode))
+}
+
+// sendCardNameChoices responds with card names, which could be completions of the given partial name.
+func (b *Bot) sendCardNameChoices(s Responder, i *discordgo.InteractionCreate, partialName string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), autocompleteTimeout)
+	defer cancel()
+
+	cardNames, err := b.leagueManager.SuggestCardNames(ctx, partialName)
+	if err != nil {
+		return errors.Join(err, b.SendChoices(s, i, nil))
+	}
+
+	return b.SendChoices(s, i, generateChoices(cardNames, ""))
+}
+
+// focusedOption returns the option the user is currently typing in, descending into sub commands if necessary.
+func focusedOption(options []*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	for _, option := range options {
+		if option.Focused {
+			return option
+		}
+
+		if option.Type == discordgo.ApplicationCommandOptionSubCommand || option.Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+			if focused := focusedOption(option.Options); focused != nil {
+				return focused
+			}
+		}
+	}
+	return nil
+}
+
+// generateChoices returns up to maxChoices choices from the given values, which contain the given filter, ignoring case.
+func generateChoices(values []string, filter string) []*discordgo.ApplicationCommandOptionChoice {
+	filter = strings.ToLower(filter)
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, min(len(values), maxChoices))
+	for _, value := range values {
+		if !strings.Contains(strings.ToLower(value), filter) {
+			continue
+		}
+
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  value,
+			Value: value,
+		})
+		if len(choices) == maxChoices {
+			break
+		}
+	}
+	return choices
+}
@@ -6,32 +6,54 @@ import (
 	"os"
 	"os/signal"
 	"progression/league"
+	"progression/repository"
+	"strings"
+	"sync"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-type InteractionFunction func(*discordgo.Session, *discordgo.InteractionCreate)
+type InteractionFunction func(Responder, *discordgo.InteractionCreate)
 
 type Bot struct {
-	session         *discordgo.Session
-	commands        []*discordgo.ApplicationCommand
-	commandHandlers map[string]InteractionFunction
-	leagueManager   *league.Manager
+	session               *discordgo.Session
+	commands              []*discordgo.ApplicationCommand
+	commandHandlers       map[string]InteractionFunction
+	autocompleteHandlers  map[string]InteractionFunction
+	componentHandlers     map[string]InteractionFunction
+	modalHandlers         map[string]InteractionFunction
+	commandVisibilities   map[string]Visibility
+	deferrals             sync.Map
+	playerNames           sync.Map
+	guildIDs              []string
+	roleCapabilities      RoleCapabilities
+	announcementChannelID string
+	leagueManager         *league.Manager
 }
 
-func New(token string, leagueManager *league.Manager) (*Bot, error) {
+// New creates a bot, whose commands are registered in the given guilds. If no guild is given, they are registered globally, which takes up to an hour to propagate.
+// Members of the guilds are granted capabilities in the league based on their roles.
+// Events of the league, e.g. a round starting, are announced in the given channel, if any, once the bot is subscribed to them with Announce.
+func New(token string, leagueManager *league.Manager, guildIDs []string, roleCapabilities RoleCapabilities, announcementChannelID string) (*Bot, error) {
 	session, err := discordgo.New("Bot " + token)
 	if err != nil {
 		return nil, err
 	}
 
 	bot := &Bot{
-		session:       session,
-		leagueManager: leagueManager,
+		session:               session,
+		guildIDs:              guildIDs,
+		roleCapabilities:      roleCapabilities,
+		announcementChannelID: announcementChannelID,
+		leagueManager:         leagueManager,
 	}
 
 	bot.commands = generateCommands()
 	bot.commandHandlers = generateCommandHandlerMap(bot)
+	bot.autocompleteHandlers = generateAutocompleteHandlerMap(bot)
+	bot.componentHandlers = generateComponentHandlerMap(bot)
+	bot.modalHandlers = generateModalHandlerMap(bot)
+	bot.commandVisibilities = generateCommandVisibilityMap()
 
 	return bot, nil
 }
@@ -53,11 +75,22 @@ func generateCommands() []*discordgo.ApplicationCommand {
 		{
 			Name:        "pool",
 			Description: "Get a list of all cards in your card pool.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "export",
+					Description: "Attach your card pool as a CSV file, including foil status and card links.",
+				},
+			},
 		},
 		{
 			Name:        "balance",
 			Description: "Check the number of wild cards & packs available to you.",
 		},
+		{
+			Name:        "ledger",
+			Description: "Get the history of your wild cards & packs.",
+		},
 		{
 			Name:        "report",
 			Description: "Report match results.",
@@ -80,6 +113,11 @@ func generateCommands() []*discordgo.ApplicationCommand {
 					Description: "The number of games in the match ending in a draw.",
 					Required:    true,
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "user",
+					Description: "Admins only: the player to report the match for, e.g. to resolve a flagged match.",
+				},
 			},
 		},
 		{
@@ -91,38 +129,68 @@ func generateCommands() []*discordgo.ApplicationCommand {
 			Description: "Get a list of all unlocked sets.",
 		},
 		{
-			Name:        "ban",
-			Description: "Ban a card.",
+			Name:                     "ban",
+			Description:              "Ban a card.",
+			DefaultMemberPermissions: &adminPermissions,
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "card_name",
-					Description: "The name of the card to ban.",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "card_name",
+					Description:  "The name of the card to ban.",
+					Required:     true,
+					Autocomplete: true,
 				},
 			},
 		},
 		{
-			Name:        "unban",
-			Description: "Unban a card.",
+			Name:                     "unban",
+			Description:              "Unban a card.",
+			DefaultMemberPermissions: &adminPermissions,
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "card_name",
-					Description: "The name of the card to unban.",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "card_name",
+					Description:  "The name of the card to unban.",
+					Required:     true,
+					Autocomplete: true,
 				},
 			},
 		},
 		{
-			Name:        "start",
-			Description: "Start a new league.",
+			Name:                     "start",
+			Description:              "Start a new league.",
+			DefaultMemberPermissions: &adminPermissions,
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "set_code",
-					Description: "The first set to make available to all players.",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "set_code",
+					Description:  "The first set to make available to all players.",
+					Required:     true,
+					Autocomplete: true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "public",
+					Description: "Reveal every player's packs in the channel.",
+				},
+			},
+		},
+		{
+			Name:                     "admin",
+			Description:              "Fix balances and card pools of players.",
+			DefaultMemberPermissions: &adminPermissions,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "grant",
+					Description: "Grant wild cards, wild packs or cards to a player.",
+					Options:     generateAdjustmentSubCommands("grant"),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "revoke",
+					Description: "Revoke wild cards, wild packs or cards from a player.",
+					Options:     generateAdjustmentSubCommands("revoke"),
 				},
 			},
 		},
@@ -136,29 +204,45 @@ func generateCommands() []*discordgo.ApplicationCommand {
 					Description: "Redeem a wild card to get a specific card from an already unlocked set.",
 					Options: []*discordgo.ApplicationCommandOption{
 						{
-							Type:        discordgo.ApplicationCommandOptionString,
-							Name:        "set_code",
-							Description: "The set the card belongs to.",
-							Required:    true,
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "set_code",
+							Description:  "The set the card belongs to.",
+							Required:     true,
+							Autocomplete: true,
 						},
 						{
-							Type:        discordgo.ApplicationCommandOptionInteger,
+							Type:        discordgo.ApplicationCommandOptionString,
 							Name:        "collector_number",
 							Description: "The collector number of the card in the given set.",
 							Required:    true,
 						},
 					},
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "card_by_name",
+					Description: "Redeem a wild card to get a specific card by name from an already unlocked set.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "card_name",
+							Description:  "The name of the card.",
+							Required:     true,
+							Autocomplete: true,
+						},
+					},
+				},
 				{
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
 					Name:        "pack",
 					Description: "Redeem one or more packs to get that many packs from an already unlocked set.",
 					Options: []*discordgo.ApplicationCommandOption{
 						{
-							Type:        discordgo.ApplicationCommandOptionString,
-							Name:        "set_code",
-							Description: "The set to which the packs belong.",
-							Required:    true,
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "set_code",
+							Description:  "The set to which the packs belong.",
+							Required:     true,
+							Autocomplete: true,
 						},
 						{
 							Type:        discordgo.ApplicationCommandOptionInteger,
@@ -166,29 +250,186 @@ func generateCommands() []*discordgo.ApplicationCommand {
 							Description: "The number of packs to open.",
 							Required:    true,
 						},
+						{
+							Type:        discordgo.ApplicationCommandOptionBoolean,
+							Name:        "public",
+							Description: "Reveal the packs to everyone in the channel instead of only you.",
+						},
 					},
 				},
 			},
 		},
+		{
+			Name:        "trade",
+			Description: "Trade cards, wild cards or packs with another player.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "offer",
+					Description: "Offer a trade, which the other player can accept, counter or decline.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionUser,
+							Name:        "user",
+							Description: "The player to trade with.",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "give",
+							Description: "What you give, e.g. \"2 IKO 1, 1 rare, 3 pack\".",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "get",
+							Description: "What you get in return, in the same format.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// generateAdjustmentSubCommands generates the sub commands shared by /admin grant and /admin revoke.
+func generateAdjustmentSubCommands(action string) []*discordgo.ApplicationCommandOption {
+	rarityChoices := make([]*discordgo.ApplicationCommandOptionChoice, len(repository.Rarities))
+	for i, rarity := range repository.Rarities {
+		rarityChoices[i] = &discordgo.ApplicationCommandOptionChoice{
+			Name:  string(rarity),
+			Value: string(rarity),
+		}
+	}
+
+	userOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionUser,
+		Name:        "user",
+		Description: fmt.Sprintf("The player to %s to or from.", action),
+		Required:    true,
+	}
+	countOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionInteger,
+		Name:        "count",
+		Description: fmt.Sprintf("The number to %s.", action),
+		Required:    true,
+		MinValue:    &minCount,
+	}
+	reasonOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "reason",
+		Description: "Why this is necessary. It will be logged.",
+		Required:    true,
+	}
+
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "wild_cards",
+			Description: "Wild cards of a single rarity.",
+			Options: []*discordgo.ApplicationCommandOption{
+				userOption,
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "rarity",
+					Description: "The rarity of the wild cards.",
+					Required:    true,
+					Choices:     rarityChoices,
+				},
+				countOption,
+				reasonOption,
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "wild_packs",
+			Description: "Wild packs.",
+			Options: []*discordgo.ApplicationCommandOption{
+				userOption,
+				countOption,
+				reasonOption,
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "card",
+			Description: "Copies of a specific card.",
+			Options: []*discordgo.ApplicationCommandOption{
+				userOption,
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "set_code",
+					Description: "The set the card belongs to.",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "collector_number",
+					Description: "The collector number of the card in the given set.",
+					Required:    true,
+				},
+				countOption,
+				reasonOption,
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "foil",
+					Description: "Whether the copies are foil.",
+				},
+			},
+		},
 	}
 }
 
 func generateCommandHandlerMap(bot *Bot) map[string]InteractionFunction {
 	commandHandlers := map[string]InteractionFunction{
-		"help":    WithErrorLogging(bot.HelpCommand),
-		"join":    WithErrorLogging(bot.JoinCommand),
-		"drop":    WithErrorLogging(bot.DropCommand),
-		"pool":    WithErrorLogging(bot.PoolCommand),
-		"balance": WithErrorLogging(bot.BalanceCommand),
-		"report":  WithErrorLogging(bot.ReportCommand),
-		"bans":    WithErrorLogging(bot.BansCommand),
-		"sets":    WithErrorLogging(bot.SetsCommand),
-		"ban":     WithErrorLogging(bot.BanCommand),
-		"unban":   WithErrorLogging(bot.UnbanCommand),
+		"help":    chain(bot.HelpCommand),
+		"join":    chain(bot.JoinCommand),
+		"drop":    chain(bot.DropCommand),
+		"pool":    chain(bot.PoolCommand),
+		"balance": chain(bot.BalanceCommand),
+		"ledger":  chain(bot.LedgerCommand),
+		"report":  chain(bot.ReportCommand),
+		"bans":    chain(bot.BansCommand),
+		"sets":    chain(bot.SetsCommand),
+		"ban":     chain(bot.BanCommand),
+		"unban":   chain(bot.UnbanCommand),
+		"start":   chain(bot.StartCommand, bot.WithDeferredResponse),
+		"redeem":  chain(bot.RedeemCommand, bot.WithDeferredResponse),
+		"admin":   chain(bot.AdminCommand, bot.WithDeferredResponse),
+		"trade":   chain(bot.TradeCommand, bot.WithDeferredResponse),
 	}
 	return commandHandlers
 }
 
+func generateAutocompleteHandlerMap(bot *Bot) map[string]InteractionFunction {
+	autocompleteHandlers := map[string]InteractionFunction{
+		"ban":    chain(bot.BanAutocomplete),
+		"unban":  chain(bot.UnbanAutocomplete),
+		"redeem": chain(bot.RedeemAutocomplete),
+		"start":  chain(bot.StartAutocomplete),
+	}
+	return autocompleteHandlers
+}
+
+// generateComponentHandlerMap maps the custom ID prefix of message components to their handlers.
+// Everything following the first colon in a custom ID is considered to be the component's arguments.
+func generateComponentHandlerMap(bot *Bot) map[string]InteractionFunction {
+	componentHandlers := map[string]InteractionFunction{
+		redeemPrintingComponentID: chain(bot.RedeemPrintingComponent, bot.WithDeferredResponse),
+		tradeAcceptComponentID:    chain(bot.TradeAcceptComponent),
+		tradeCounterComponentID:   chain(bot.TradeCounterComponent),
+		tradeDeclineComponentID:   chain(bot.TradeDeclineComponent),
+	}
+	return componentHandlers
+}
+
+// generateModalHandlerMap maps the custom ID prefix of modals to their handlers, following the same convention as message components.
+func generateModalHandlerMap(bot *Bot) map[string]InteractionFunction {
+	modalHandlers := map[string]InteractionFunction{
+		tradeCounterComponentID: chain(bot.TradeCounterModal, bot.WithDeferredResponse),
+	}
+	return modalHandlers
+}
+
 func (b *Bot) Start() error {
 	slog.Info("Adding Ready Handler...")
 	b.session.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
@@ -199,8 +440,27 @@ func (b *Bot) Start() error {
 
 	slog.Info("Adding Interaction Handler...")
 	b.session.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		if h, ok := b.commandHandlers[i.ApplicationCommandData().Name]; ok {
-			h(s, i)
+		var handlers map[string]InteractionFunction
+		var name string
+		switch i.Type {
+		case discordgo.InteractionApplicationCommand:
+			handlers = b.commandHandlers
+			name = i.ApplicationCommandData().Name
+		case discordgo.InteractionApplicationCommandAutocomplete:
+			handlers = b.autocompleteHandlers
+			name = i.ApplicationCommandData().Name
+		case discordgo.InteractionMessageComponent:
+			handlers = b.componentHandlers
+			name, _, _ = strings.Cut(i.MessageComponentData().CustomID, ":")
+		case discordgo.InteractionModalSubmit:
+			handlers = b.modalHandlers
+			name, _, _ = strings.Cut(i.ModalSubmitData().CustomID, ":")
+		default:
+			return
+		}
+
+		if h, ok := handlers[name]; ok {
+			h(sessionResponder{s}, i)
 		}
 	})
 
@@ -209,63 +469,104 @@ func (b *Bot) Start() error {
 		return err
 	}
 
-	slog.Info("Adding commands...")
-	registeredCommands := make([]*discordgo.ApplicationCommand, len(b.commands))
-	for i, v := range b.commands {
-		cmd, err := b.session.ApplicationCommandCreate(b.session.State.User.ID, "", v)
-		if err != nil {
-			slog.Error("Cannot create command", "command", v.Name, "error", err)
-			return err
-		}
-		registeredCommands[i] = cmd
-	}
-
 	defer b.session.Close()
 
+	slog.Info("Registering commands...")
+	err = b.registerCommands()
+	if err != nil {
+		return err
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt)
 	slog.Info("Press Ctrl+C to exit")
 	<-stop
 
-	slog.Info("Removing commands...")
-	// // We need to fetch the commands, since deleting requires the command ID.
-	// // We are doing this from the returned commands on line 375, because using
-	// // this will delete all the commands, which might not be desirable, so we
-	// // are deleting only the commands that we added.
-	// registeredCommands, err := s.ApplicationCommands(s.State.User.ID, *GuildID)
-	// if err != nil {
-	// 	log.Fatalf("Could not fetch registered commands: %v", err)
-	// }
-
-	for _, v := range registeredCommands {
-		err = b.session.ApplicationCommandDelete(b.session.State.User.ID, "", v.ID)
-		if err != nil {
-			slog.Error("Cannot delete command", "command", v.Name, "error", err)
-			return err
-		}
-	}
-
 	slog.Info("Gracefully shutting down.")
 
 	return nil
 }
 
-func (b *Bot) SendMessage(s *discordgo.Session, i *discordgo.InteractionCreate, msg string) error {
+// SendMessage responds with the given message, visible as defined for the command.
+func (b *Bot) SendMessage(s Responder, i *discordgo.InteractionCreate, msg string) error {
+	return b.respond(s, i, b.visibility(i), &discordgo.InteractionResponseData{
+		Content: msg,
+	})
+}
+
+// SendError responds with the given message about a failure, which only the user can see.
+func (b *Bot) SendError(s Responder, i *discordgo.InteractionCreate, msg string) error {
+	return b.respond(s, i, VisibilityEphemeral, &discordgo.InteractionResponseData{
+		Content: msg,
+	})
+}
+
+// SendResult responds with the outcome of a command. If it failed, only the user can see the message.
+func (b *Bot) SendResult(s Responder, i *discordgo.InteractionCreate, msg string, err error) error {
+	if err != nil {
+		return b.SendError(s, i, msg)
+	}
+	return b.SendMessage(s, i, msg)
+}
+
+func (b *Bot) SendComponents(s Responder, i *discordgo.InteractionCreate, msg string, components []discordgo.MessageComponent) error {
+	return b.respond(s, i, b.visibility(i), &discordgo.InteractionResponseData{
+		Content:    msg,
+		Components: components,
+	})
+}
+
+// SendEmbeds responds with the given embeds, split into as many messages as necessary.
+func (b *Bot) SendEmbeds(s Responder, i *discordgo.InteractionCreate, msg string, embeds []*discordgo.MessageEmbed, visibility Visibility) error {
+	return b.respond(s, i, visibility, &discordgo.InteractionResponseData{
+		Content: msg,
+		Embeds:  embeds,
+	})
+}
+
+// UpdateMessage replaces the content of the message containing the component used in the interaction and removes all of its components.
+func (b *Bot) UpdateMessage(s Responder, i *discordgo.InteractionCreate, msg string) error {
+	if _, deferred := b.deferrals.Load(i.ID); deferred {
+		_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content:    &msg,
+			Components: &[]discordgo.MessageComponent{},
+		})
+		return err
+	}
+
 	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Type: discordgo.InteractionResponseUpdateMessage,
 		Data: &discordgo.InteractionResponseData{
-			Content: msg,
+			Content:    msg,
+			Components: []discordgo.MessageComponent{},
 		},
 	})
 }
 
-func WithErrorLogging(f func(*discordgo.Session, *discordgo.InteractionCreate) error) InteractionFunction {
-	wrapFunc := func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		userID := i.Member.User.ID
-		err := f(s, i)
-		if err != nil {
-			slog.Error("failed to report error to user", "error", err, "user", userID)
-		}
-	}
-	return wrapFunc
+// SendModal opens a form with the given text inputs. Its submission is handled by the modal handler matching the custom ID.
+func (b *Bot) SendModal(s Responder, i *discordgo.InteractionCreate, customID, title string, components []discordgo.MessageComponent) error {
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID:   customID,
+			Title:      title,
+			Components: components,
+		},
+	})
+}
+
+func (b *Bot) SendFile(s Responder, i *discordgo.InteractionCreate, msg string, file *discordgo.File) error {
+	return b.respond(s, i, b.visibility(i), &discordgo.InteractionResponseData{
+		Content: msg,
+		Files:   []*discordgo.File{file},
+	})
+}
+
+func (b *Bot) SendChoices(s Responder, i *discordgo.InteractionCreate, choices []*discordgo.ApplicationCommandOptionChoice) error {
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
 }
@@ -1,22 +1,29 @@
 package discord
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"progression/league"
+	"progression/packGenerator"
 	"progression/repository"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-func (b *Bot) HelpCommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+func (b *Bot) HelpCommand(s Responder, i *discordgo.InteractionCreate) error {
 	message := "Please check the command section in the bot README here: https://github.com/suroh1994/progression-discord-bot?tab=readme-ov-file#commands"
 
 	return b.SendMessage(s, i, message)
 }
 
-func (b *Bot) SetsCommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+func (b *Bot) SetsCommand(s Responder, i *discordgo.InteractionCreate) error {
 	var message string
 	sets, err := b.leagueManager.GetSets()
 	if err != nil {
@@ -35,10 +42,10 @@ func (b *Bot) SetsCommand(s *discordgo.Session, i *discordgo.InteractionCreate)
 		}
 	}
 
-	return b.SendMessage(s, i, message)
+	return b.SendResult(s, i, message, err)
 }
 
-func (b *Bot) BansCommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+func (b *Bot) BansCommand(s Responder, i *discordgo.InteractionCreate) error {
 	var message string
 	bans, err := b.leagueManager.GetBannedCards()
 	if err != nil {
@@ -57,16 +64,16 @@ func (b *Bot) BansCommand(s *discordgo.Session, i *discordgo.InteractionCreate)
 		}
 	}
 
-	return b.SendMessage(s, i, message)
+	return b.SendResult(s, i, message, err)
 }
 
-func (b *Bot) BanCommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	userID := i.Member.User.ID
+func (b *Bot) BanCommand(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
 	commandData := i.ApplicationCommandData()
 	cardName := commandData.Options[0].StringValue()
 
 	var message string
-	err := b.leagueManager.BanCard(userID, cardName)
+	err := b.leagueManager.BanCard(b.authorizedContext(s, i), userID, cardName)
 	if err != nil {
 		switch {
 		case errors.Is(err, league.ErrPlayerNotAdmin):
@@ -78,16 +85,16 @@ func (b *Bot) BanCommand(s *discordgo.Session, i *discordgo.InteractionCreate) e
 		message = fmt.Sprintf("Banned %s.", cardName)
 	}
 
-	return b.SendMessage(s, i, message)
+	return b.SendResult(s, i, message, err)
 }
 
-func (b *Bot) UnbanCommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	userID := i.Member.User.ID
+func (b *Bot) UnbanCommand(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
 	commandData := i.ApplicationCommandData()
 	cardName := commandData.Options[0].StringValue()
 
 	var message string
-	err := b.leagueManager.UnbanCard(userID, cardName)
+	err := b.leagueManager.UnbanCard(b.authorizedContext(s, i), userID, cardName)
 	if err != nil {
 		switch {
 		case errors.Is(err, league.ErrPlayerNotAdmin):
@@ -99,11 +106,58 @@ func (b *Bot) UnbanCommand(s *discordgo.Session, i *discordgo.InteractionCreate)
 		message = fmt.Sprintf("Unbanned %s.", cardName)
 	}
 
-	return b.SendMessage(s, i, message)
+	return b.SendResult(s, i, message, err)
 }
 
-func (b *Bot) DropCommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	userID := i.Member.User.ID
+func (b *Bot) StartCommand(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
+	setCode := i.ApplicationCommandData().GetOption("set_code").StringValue()
+	public := false
+	if option := i.ApplicationCommandData().GetOption("public"); option != nil {
+		public = option.BoolValue()
+	}
+
+	var message string
+	progress := b.progressReporter(s, i, "Opening packs… %d/%d players done.")
+	playerPacks, err := b.leagueManager.StartRound(b.authorizedContext(s, i), userID, setCode, progress)
+	if err != nil {
+		switch {
+		case errors.Is(err, league.ErrPlayerNotAdmin):
+			message = "You are not an admin."
+		case errors.Is(err, repository.ErrLeagueAlreadyOngoing):
+			message = "Another league is already ongoing."
+		case errors.Is(err, packGenerator.ErrSetNotFound):
+			message = "The given set code is not valid."
+		default:
+			message = "Error starting league: " + err.Error()
+		}
+		return b.SendError(s, i, message)
+	}
+
+	message = fmt.Sprintf("The league has started with %d players and %s as the first set!", len(playerPacks), strings.ToUpper(setCode))
+	if deadline, found, err := b.leagueManager.GetRoundDeadline(); err == nil && found {
+		message += fmt.Sprintf(" Report your matches by %s.", formatTimestamp(deadline))
+	}
+	if !public {
+		for _, playerID := range slices.Sorted(maps.Keys(playerPacks)) {
+			err = b.SendDirectMessage(s, playerID, "Your packs for the new league:", generatePackEmbeds(playerID, playerPacks[playerID]))
+			if err != nil {
+				slog.Warn("failed to send packs in a direct message", "error", err, "user", playerID)
+			}
+		}
+		return b.SendMessage(s, i, message+" Every player has received their packs in a direct message. Use /pool to see your cards.")
+	}
+
+	var embeds []*discordgo.MessageEmbed
+	for _, playerID := range slices.Sorted(maps.Keys(playerPacks)) {
+		embeds = append(embeds, generatePackEmbeds(playerID, playerPacks[playerID])...)
+	}
+
+	return b.SendEmbeds(s, i, message, embeds, VisibilityPublic)
+}
+
+func (b *Bot) DropCommand(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
 
 	var message string
 	err := b.leagueManager.DropPlayer(userID)
@@ -120,33 +174,114 @@ func (b *Bot) DropCommand(s *discordgo.Session, i *discordgo.InteractionCreate)
 		message = "You have been successfully removed from the league."
 	}
 
-	return b.SendMessage(s, i, message)
+	return b.SendResult(s, i, message, err)
 }
 
-func (b *Bot) BalanceCommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	userID := i.Member.User.ID
+func (b *Bot) BalanceCommand(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
 	var message string
 	player, err := b.leagueManager.GetPlayerBalance(userID)
 	if err != nil {
 		message = "Error getting your balance: " + err.Error()
 	} else {
-		message = fmt.Sprintf("Wild cards: %d\nWild packs: %d", player.WildCards, player.WildPacks)
+		message = formatBalance(player, b.leagueManager.Config())
 	}
 
-	return b.SendMessage(s, i, message)
+	return b.SendResult(s, i, message, err)
 }
 
-func (b *Bot) PoolCommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	userID := i.Member.User.ID
-	var message string
-	cards, err := b.leagueManager.GetPlayerCards(userID)
+func formatBalance(player repository.Player, config league.Config) string {
+	var builder strings.Builder
+	builder.WriteString("Wild cards:\n")
+	for _, rarity := range repository.Rarities {
+		builder.WriteString(fmt.Sprintf("- %s: %d\n", rarity, player.WildCards(rarity)))
+	}
+	builder.WriteString(fmt.Sprintf("Wild packs: %d", player.WildPacks))
+	if config.VaultEnabled {
+		builder.WriteString(fmt.Sprintf("\nVault: %d/%d", player.VaultProgress, config.VaultSize))
+	}
+	return builder.String()
+}
+
+// maxLedgerEntries is the number of most recent ledger entries shown, keeping the message below discord's length limit.
+const maxLedgerEntries = 20
+
+func (b *Bot) LedgerCommand(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
+
+	entries, err := b.leagueManager.GetLedger(userID)
 	if err != nil {
-		message = "Error getting your card pool: " + err.Error()
+		return b.SendError(s, i, "Error getting your ledger: "+err.Error())
+	}
+
+	differences, err := b.leagueManager.ReconcileBalance(userID)
+	if err != nil {
+		return b.SendError(s, i, "Error reconciling your balance: "+err.Error())
+	}
+
+	return b.SendMessage(s, i, formatLedger(userID, entries, differences))
+}
+
+func formatLedger(userID string, entries []repository.LedgerEntry, differences map[repository.Currency]int) string {
+	var builder strings.Builder
+	if len(entries) == 0 {
+		builder.WriteString("Your ledger is empty.")
 	} else {
-		message = formatCardList(cards)
+		if len(entries) > maxLedgerEntries {
+			builder.WriteString(fmt.Sprintf("Showing the latest %d of %d entries.\n", maxLedgerEntries, len(entries)))
+			entries = entries[len(entries)-maxLedgerEntries:]
+		}
+
+		builder.WriteString("```\n")
+		for _, entry := range entries {
+			actor := entry.Actor
+			if actor == userID {
+				actor = "you"
+			}
+			builder.WriteString(fmt.Sprintf("%s round %d %+d %s (%s by %s)",
+				entry.CreatedAt.Format(time.DateOnly), entry.Round, entry.Amount, entry.Currency, entry.Reason, actor))
+			if entry.Note != "" {
+				builder.WriteString(": " + entry.Note)
+			}
+			builder.WriteString("\n")
+		}
+		builder.WriteString("```")
 	}
 
-	return b.SendMessage(s, i, message)
+	for _, currency := range slices.Sorted(maps.Keys(differences)) {
+		builder.WriteString(fmt.Sprintf("\nYour %s balance differs from the ledger by %+d.", currency, differences[currency]))
+	}
+
+	return builder.String()
+}
+
+func (b *Bot) PoolCommand(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
+	commandData := i.ApplicationCommandData()
+	export := false
+	if option := commandData.GetOption("export"); option != nil {
+		export = option.BoolValue()
+	}
+
+	cards, err := b.leagueManager.GetPlayerCards(userID)
+	if err != nil {
+		return b.SendError(s, i, "Error getting your card pool: "+err.Error())
+	}
+
+	if !export || len(cards) == 0 {
+		return b.SendMessage(s, i, formatCardList(cards))
+	}
+
+	csvExport, err := league.ExportCardList(cards)
+	if err != nil {
+		return b.SendError(s, i, "Error exporting your card pool: "+err.Error())
+	}
+
+	return b.SendFile(s, i, fmt.Sprintf("Your card pool contains %d different cards.", len(cards)), &discordgo.File{
+		Name:        "pool.csv",
+		ContentType: "text/csv",
+		Reader:      bytes.NewReader(csvExport),
+	})
 }
 
 func formatCardList(cards []repository.Card) string {
@@ -156,14 +291,18 @@ func formatCardList(cards []repository.Card) string {
 	var builder strings.Builder
 	builder.WriteString("```\n")
 	for _, card := range cards {
-		builder.WriteString(fmt.Sprintf("%d %s\n", card.Count, card.Name))
+		builder.WriteString(fmt.Sprintf("%d %s", card.Count, card.Name))
+		if card.Foil {
+			builder.WriteString(" (foil)")
+		}
+		builder.WriteString("\n")
 	}
 	builder.WriteString("```")
 	return builder.String()
 }
 
-func (b *Bot) JoinCommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	userID := i.Member.User.ID
+func (b *Bot) JoinCommand(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
 
 	var message string
 	err := b.leagueManager.JoinLeague(userID)
@@ -178,20 +317,26 @@ func (b *Bot) JoinCommand(s *discordgo.Session, i *discordgo.InteractionCreate)
 		message = "You've joined the league."
 	}
 
-	return b.SendMessage(s, i, message)
+	return b.SendResult(s, i, message, err)
 }
 
-func (b *Bot) ReportCommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	userID := i.Member.User.ID
+func (b *Bot) ReportCommand(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
 	commandData := i.ApplicationCommandData()
 	wins := commandData.GetOption("games_won").IntValue()
 	losses := commandData.GetOption("games_lost").IntValue()
 	draws := commandData.GetOption("draws").IntValue()
+	playerID := userID
+	if option := commandData.GetOption("user"); option != nil {
+		playerID = option.UserValue(nil).ID
+	}
 
 	var message string
-	err := b.leagueManager.ReportMatch(userID, int(wins), int(losses), int(draws))
+	err := b.leagueManager.ReportMatch(b.authorizedContext(s, i), userID, playerID, int(wins), int(losses), int(draws))
 	if err != nil {
 		switch {
+		case errors.Is(err, league.ErrPlayerNotAdmin):
+			message = "Only admins can report matches of other players."
 		case errors.Is(err, league.ErrInvalidMatchResult):
 			message = fmt.Sprintf("The given match result is invalid. Given: %d wins, %d losses and %d draws.", wins, losses, int(draws))
 		case errors.Is(err, league.ErrMatchAlreadyReported):
@@ -199,9 +344,142 @@ func (b *Bot) ReportCommand(s *discordgo.Session, i *discordgo.InteractionCreate
 		default:
 			message = "Error reporting match result: " + err.Error()
 		}
+	} else if playerID != userID {
+		message = fmt.Sprintf("Match result of <@%s> reported successfully!", playerID)
 	} else {
 		message = "Match result reported successfully!"
 	}
 
-	return b.SendMessage(s, i, message)
+	return b.SendResult(s, i, message, err)
+}
+
+// redeemPrintingComponentID is the custom ID prefix of the select menu used to pick a printing when redeeming a card by name.
+// It is followed by the ID of the user who is allowed to pick the printing.
+const redeemPrintingComponentID = "redeem_printing"
+
+func (b *Bot) RedeemCommand(s Responder, i *discordgo.InteractionCreate) error {
+	subCommand := i.ApplicationCommandData().Options[0]
+	switch subCommand.Name {
+	case "card":
+		setCode := subCommand.GetOption("set_code").StringValue()
+		collectorNumber := subCommand.GetOption("collector_number").StringValue()
+		return b.redeemCard(s, i, setCode, collectorNumber)
+	case "card_by_name":
+		cardName := subCommand.GetOption("card_name").StringValue()
+		return b.redeemCardByName(s, i, cardName)
+	case "pack":
+		setCode := subCommand.GetOption("set_code").StringValue()
+		count := subCommand.GetOption("count").IntValue()
+		return b.redeemPacks(s, i, setCode, int(count))
+	default:
+		return b.SendError(s, i, fmt.Sprintf("Unknown sub command %q.", subCommand.Name))
+	}
+}
+
+func (b *Bot) redeemCard(s Responder, i *discordgo.InteractionCreate, setCode, collectorNumber string) error {
+	userID := interactionUserID(i)
+
+	card, err := b.leagueManager.RedeemCard(context.Background(), userID, setCode, collectorNumber)
+	return b.SendResult(s, i, formatRedeemCardResult(card, err), err)
+}
+
+func (b *Bot) redeemCardByName(s Responder, i *discordgo.InteractionCreate, cardName string) error {
+	userID := interactionUserID(i)
+
+	printings, err := b.leagueManager.FindCardPrintings(context.Background(), cardName)
+	if err != nil {
+		return b.SendError(s, i, formatRedeemCardResult(repository.Card{}, err))
+	}
+
+	if len(printings) == 1 {
+		card, err := b.leagueManager.RedeemCard(context.Background(), userID, printings[0].Set, printings[0].CollectorNumber)
+		return b.SendResult(s, i, formatRedeemCardResult(card, err), err)
+	}
+
+	options := make([]discordgo.SelectMenuOption, 0, min(len(printings), maxChoices))
+	for _, printing := range printings[:min(len(printings), maxChoices)] {
+		options = append(options, discordgo.SelectMenuOption{
+			Label: fmt.Sprintf("%s #%s", printing.Set, printing.CollectorNumber),
+			Value: fmt.Sprintf("%s|%s", printing.Set, printing.CollectorNumber),
+		})
+	}
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:    discordgo.StringSelectMenu,
+					CustomID:    redeemPrintingComponentID + ":" + userID,
+					Placeholder: "Choose a printing",
+					Options:     options,
+				},
+			},
+		},
+	}
+
+	message := fmt.Sprintf("%s has been printed in multiple unlocked sets. Which printing do you want to redeem?", printings[0].Name)
+	return b.SendComponents(s, i, message, components)
+}
+
+func (b *Bot) RedeemPrintingComponent(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
+	componentData := i.MessageComponentData()
+
+	_, ownerID, _ := strings.Cut(componentData.CustomID, ":")
+	if ownerID != userID {
+		return b.SendError(s, i, "You can only pick a printing for cards you are redeeming yourself.")
+	}
+
+	setCode, collectorNumber, _ := strings.Cut(componentData.Values[0], "|")
+	card, err := b.leagueManager.RedeemCard(context.Background(), userID, setCode, collectorNumber)
+	return b.UpdateMessage(s, i, formatRedeemCardResult(card, err))
+}
+
+func formatRedeemCardResult(card repository.Card, err error) string {
+	if err == nil {
+		return fmt.Sprintf("Added %s (%s #%s) to your card pool.", card.Name, card.Set, card.CollectorNumber)
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrPlayerNotFound):
+		return "You are not part of the current league."
+	case errors.Is(err, league.ErrPlayerAlreadyDropped):
+		return "You have already dropped from the league."
+	case errors.Is(err, league.ErrInsufficientWildCards):
+		return "You have no wild cards of the card's rarity left."
+	case errors.Is(err, league.ErrSetNotUnlocked):
+		return "The given set is not unlocked."
+	case errors.Is(err, league.ErrCardNotFound):
+		return "The given card does not exist in any unlocked set."
+	case errors.Is(err, league.ErrMaxCopiesOwned):
+		return "You already own the maximum number of copies of this card."
+	default:
+		return "Error redeeming card: " + err.Error()
+	}
+}
+
+func (b *Bot) redeemPacks(s Responder, i *discordgo.InteractionCreate, setCode string, count int) error {
+	userID := interactionUserID(i)
+
+	var message string
+	packs, err := b.leagueManager.RedeemPacks(context.Background(), userID, setCode, count)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrPlayerNotFound):
+			message = "You are not part of the current league."
+		case errors.Is(err, league.ErrPlayerAlreadyDropped):
+			message = "You have already dropped from the league."
+		case errors.Is(err, league.ErrInvalidPackCount):
+			message = "You have to redeem at least one pack."
+		case errors.Is(err, league.ErrInsufficientWildPacks):
+			message = "You don't have enough wild packs left."
+		case errors.Is(err, league.ErrSetNotUnlocked):
+			message = "The given set is not unlocked."
+		default:
+			message = "Error redeeming packs: " + err.Error()
+		}
+		return b.SendError(s, i, message)
+	}
+
+	return b.SendEmbeds(s, i, fmt.Sprintf("<@%s> opened %d packs.", userID, len(packs)), generatePackEmbeds(userID, packs), b.visibility(i))
 }
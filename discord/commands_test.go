@@ -0,0 +1,248 @@
+package discord
+
+import (
+	"progression/league"
+	"progression/repository"
+	"progression/repository/repositorytest"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUserID    = "player"
+	testAdminID   = "admin"
+	testChannelID = "announcements"
+)
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(*testing.T, *repositorytest.MemoryDataStore)
+		dmDisabled  bool
+		interaction *discordgo.InteractionCreate
+	}{
+		{
+			name:        "help",
+			interaction: newCommandInteraction(testUserID, "help"),
+		},
+		{
+			name:        "join",
+			interaction: newCommandInteraction(testUserID, "join"),
+		},
+		{
+			name: "join_already_joined",
+			setup: func(t *testing.T, dataStore *repositorytest.MemoryDataStore) {
+				require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: testUserID}))
+			},
+			interaction: newCommandInteraction(testUserID, "join"),
+		},
+		{
+			name: "drop",
+			setup: func(t *testing.T, dataStore *repositorytest.MemoryDataStore) {
+				require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: testUserID}))
+			},
+			interaction: newCommandInteraction(testUserID, "drop"),
+		},
+		{
+			name:        "drop_not_in_league",
+			interaction: newCommandInteraction(testUserID, "drop"),
+		},
+		{
+			name: "drop_already_dropped",
+			setup: func(t *testing.T, dataStore *repositorytest.MemoryDataStore) {
+				require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: testUserID, Dropped: true}))
+			},
+			interaction: newCommandInteraction(testUserID, "drop"),
+		},
+		{
+			name: "balance",
+			setup: func(t *testing.T, dataStore *repositorytest.MemoryDataStore) {
+				require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: testUserID, WildRares: 2, WildPacks: 1}))
+			},
+			interaction: newCommandInteraction(testUserID, "balance"),
+		},
+		{
+			name:        "balance_not_in_league",
+			interaction: newCommandInteraction(testUserID, "balance"),
+		},
+		{
+			name: "ledger",
+			setup: func(t *testing.T, dataStore *repositorytest.MemoryDataStore) {
+				require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: testUserID, WildRares: 2}))
+				require.NoError(t, dataStore.AppendLedgerEntries([]repository.LedgerEntry{{
+					PlayerId:  testUserID,
+					Currency:  repository.WildCardCurrency(repository.RarityRare),
+					Amount:    1,
+					Reason:    repository.LedgerReasonRoundReward,
+					Actor:     "system",
+					CreatedAt: time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC),
+				}}))
+			},
+			interaction: newCommandInteraction(testUserID, "ledger"),
+		},
+		{
+			name: "pool",
+			setup: func(t *testing.T, dataStore *repositorytest.MemoryDataStore) {
+				dataStore.AddCards(testUserID,
+					repository.Card{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 2},
+					repository.Card{Name: "Lurrus of the Dream-Den", Set: "IKO", CollectorNumber: "226", Foil: true, Count: 1},
+				)
+			},
+			interaction: newCommandInteraction(testUserID, "pool"),
+		},
+		{
+			name: "pool_dm_disabled",
+			setup: func(t *testing.T, dataStore *repositorytest.MemoryDataStore) {
+				dataStore.AddCards(testUserID, repository.Card{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 2})
+			},
+			dmDisabled:  true,
+			interaction: newCommandInteraction(testUserID, "pool"),
+		},
+		{
+			name:        "report_invalid_result",
+			interaction: newCommandInteraction(testUserID, "report", intOption("games_won", 0), intOption("games_lost", 0), intOption("draws", 0)),
+		},
+		{
+			name: "report_already_reported",
+			setup: func(t *testing.T, dataStore *repositorytest.MemoryDataStore) {
+				require.NoError(t, dataStore.StartLeague())
+				require.NoError(t, dataStore.StorePairings([]repository.Pairing{{Player1: testUserID, Player2: "opponent", Wins1: 2}}))
+			},
+			interaction: newCommandInteraction(testUserID, "report", intOption("games_won", 2), intOption("games_lost", 1), intOption("draws", 0)),
+		},
+		{
+			name:        "report_for_other_not_admin",
+			interaction: newCommandInteraction(testUserID, "report", intOption("games_won", 2), intOption("games_lost", 0), intOption("draws", 0), userOption("user", "opponent")),
+		},
+		{
+			name: "bans",
+			setup: func(t *testing.T, dataStore *repositorytest.MemoryDataStore) {
+				require.NoError(t, dataStore.BanCard("Lurrus of the Dream-Den"))
+			},
+			interaction: newCommandInteraction(testUserID, "bans"),
+		},
+		{
+			name: "sets",
+			setup: func(t *testing.T, dataStore *repositorytest.MemoryDataStore) {
+				require.NoError(t, dataStore.StoreSets([]repository.Set{{SetCode: "IKO"}, {SetCode: "M21"}}))
+			},
+			interaction: newCommandInteraction(testUserID, "sets"),
+		},
+		{
+			name: "ban",
+			setup: func(t *testing.T, dataStore *repositorytest.MemoryDataStore) {
+				require.NoError(t, dataStore.MakeAdmin(testAdminID))
+			},
+			interaction: newCommandInteraction(testAdminID, "ban", stringOption("card_name", "Lurrus of the Dream-Den")),
+		},
+		{
+			name:        "ban_not_admin",
+			interaction: newCommandInteraction(testUserID, "ban", stringOption("card_name", "Lurrus of the Dream-Den")),
+		},
+		{
+			name:        "unban_not_admin",
+			interaction: newCommandInteraction(testUserID, "unban", stringOption("card_name", "Lurrus of the Dream-Den")),
+		},
+		{
+			name:        "start_not_admin",
+			interaction: newCommandInteraction(testUserID, "start", stringOption("set_code", "IKO")),
+		},
+		{
+			name: "admin_not_admin",
+			interaction: newCommandInteraction(testUserID, "admin", subCommandGroupOption("grant", subCommandOption("wild_packs",
+				userOption("user", testUserID), intOption("count", 5), stringOption("reason", "because")))),
+		},
+		{
+			name:        "redeem_pack_invalid_count",
+			interaction: newCommandInteraction(testUserID, "redeem", subCommandOption("pack", stringOption("set_code", "IKO"), intOption("count", 0))),
+		},
+		{
+			name:        "redeem_pack_public_invalid_count",
+			interaction: newCommandInteraction(testUserID, "redeem", subCommandOption("pack", stringOption("set_code", "IKO"), intOption("count", 0), boolOption("public", true))),
+		},
+		{
+			name:        "trade_invalid_item",
+			interaction: newCommandInteraction(testUserID, "trade", subCommandOption("offer", userOption("user", "opponent"), stringOption("give", "two rares"))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataStore := repositorytest.NewMemoryDataStore()
+			if tt.setup != nil {
+				tt.setup(t, dataStore)
+			}
+			bot := newTestBot(t, dataStore, nil)
+			responder := &recordingResponder{dmDisabled: tt.dmDisabled}
+
+			handler, found := bot.commandHandlers[tt.interaction.ApplicationCommandData().Name]
+			assert.True(t, found, "no handler registered for the command")
+			handler(responder, tt.interaction)
+
+			assertGolden(t, tt.name, responder.calls)
+		})
+	}
+}
+
+func TestCommands_role_capabilities(t *testing.T) {
+	dataStore := repositorytest.NewMemoryDataStore()
+	bot := newTestBot(t, dataStore, RoleCapabilities{
+		"guild": {"League Organizer": {league.CapabilityBan}},
+	})
+	responder := &recordingResponder{roles: map[string]*discordgo.Role{
+		"organizer": {ID: "organizer", Name: "League Organizer"},
+	}}
+
+	interaction := newCommandInteraction(testUserID, "ban", stringOption("card_name", "Lurrus of the Dream-Den"))
+	interaction.Member.Roles = []string{"organizer"}
+	bot.commandHandlers["ban"](responder, interaction)
+
+	bans, err := dataStore.GetBannedCards()
+	require.NoError(t, err)
+	assert.Equal(t, []repository.Ban{{CardName: "Lurrus of the Dream-Den"}}, bans)
+	assertGolden(t, "ban_by_role", responder.calls)
+}
+
+func TestAutocomplete(t *testing.T) {
+	focused := func(option *discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+		option.Focused = true
+		return option
+	}
+
+	tests := []struct {
+		name        string
+		interaction *discordgo.InteractionCreate
+	}{
+		{
+			name:        "autocomplete_start_set_code",
+			interaction: newCommandInteraction(testUserID, "start", focused(stringOption("set_code", "m"))),
+		},
+		{
+			name:        "autocomplete_redeem_set_code",
+			interaction: newCommandInteraction(testUserID, "redeem", subCommandOption("pack", focused(stringOption("set_code", "i")))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataStore := repositorytest.NewMemoryDataStore()
+			require.NoError(t, dataStore.StartLeague())
+			for _, setCode := range []string{"IKO", "M21", "M20"} {
+				require.NoError(t, dataStore.UnlockSet(setCode, 0))
+			}
+			bot := newTestBot(t, dataStore, nil)
+			responder := &recordingResponder{}
+			tt.interaction.Type = discordgo.InteractionApplicationCommandAutocomplete
+
+			handler, found := bot.autocompleteHandlers[tt.interaction.ApplicationCommandData().Name]
+			assert.True(t, found, "no autocomplete handler registered for the command")
+			handler(responder, tt.interaction)
+
+			assertGolden(t, tt.name, responder.calls)
+		})
+	}
+}
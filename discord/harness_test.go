@@ -0,0 +1,143 @@
+package discord
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"progression/league"
+	"progression/repository"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// recordedCall is a single call to discord's API recorded by the recordingResponder.
+type recordedCall struct {
+	Method string
+	Data   any
+}
+
+// recordingResponder records all responses instead of sending them to discord.
+type recordingResponder struct {
+	calls []recordedCall
+	roles map[string]*discordgo.Role
+	// dmDisabled makes creating direct message channels fail, like it does for users who don't accept direct messages.
+	dmDisabled bool
+}
+
+func (r *recordingResponder) InteractionRespond(_ *discordgo.Interaction, resp *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+	r.calls = append(r.calls, recordedCall{Method: "InteractionRespond", Data: resp})
+	return nil
+}
+
+func (r *recordingResponder) InteractionResponseEdit(_ *discordgo.Interaction, newresp *discordgo.WebhookEdit, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	r.calls = append(r.calls, recordedCall{Method: "InteractionResponseEdit", Data: newresp})
+	return &discordgo.Message{}, nil
+}
+
+func (r *recordingResponder) InteractionResponseDelete(_ *discordgo.Interaction, _ ...discordgo.RequestOption) error {
+	r.calls = append(r.calls, recordedCall{Method: "InteractionResponseDelete"})
+	return nil
+}
+
+func (r *recordingResponder) FollowupMessageCreate(_ *discordgo.Interaction, _ bool, data *discordgo.WebhookParams, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	r.calls = append(r.calls, recordedCall{Method: "FollowupMessageCreate", Data: data})
+	return &discordgo.Message{}, nil
+}
+
+func (r *recordingResponder) UserChannelCreate(recipientID string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if r.dmDisabled {
+		return nil, discordgo.ErrUnauthorized
+	}
+	return &discordgo.Channel{ID: "dm-" + recipientID}, nil
+}
+
+func (r *recordingResponder) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	r.calls = append(r.calls, recordedCall{Method: "ChannelMessageSendComplex " + channelID, Data: data})
+	return &discordgo.Message{}, nil
+}
+
+func (r *recordingResponder) Role(_, roleID string) (*discordgo.Role, error) {
+	role, found := r.roles[roleID]
+	if !found {
+		return nil, discordgo.ErrStateNotFound
+	}
+	return role, nil
+}
+
+// newTestBot creates a bot backed by the given datastore, which never connects to discord.
+func newTestBot(t *testing.T, dataStore repository.DataStore, roleCapabilities RoleCapabilities) *Bot {
+	t.Helper()
+
+	leagueManager := league.NewLeagueManager(dataStore, nil, nil, league.DefaultConfig())
+	bot, err := New("", leagueManager, nil, roleCapabilities, testChannelID)
+	require.NoError(t, err)
+	return bot
+}
+
+// newCommandInteraction builds the payload discord sends when the given user invokes a command in a guild.
+func newCommandInteraction(userID, command string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:      "interaction-" + command,
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: "guild",
+			Member: &discordgo.Member{
+				User: &discordgo.User{ID: userID},
+			},
+			Data: discordgo.ApplicationCommandInteractionData{
+				Name:    command,
+				Options: options,
+			},
+		},
+	}
+}
+
+func stringOption(name, value string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionString, Value: value}
+}
+
+func intOption(name string, value int) *discordgo.ApplicationCommandInteractionDataOption {
+	// discord's JSON payloads only contain floating point numbers.
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionInteger, Value: float64(value)}
+}
+
+func boolOption(name string, value bool) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionBoolean, Value: value}
+}
+
+func userOption(name, userID string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionUser, Value: userID}
+}
+
+func subCommandOption(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionSubCommand, Options: options}
+}
+
+func subCommandGroupOption(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionSubCommandGroup, Options: options}
+}
+
+// assertGolden compares the recorded calls with the golden file of the given name. Run the tests with -update to rewrite the golden files.
+func assertGolden(t *testing.T, name string, calls []recordedCall) {
+	t.Helper()
+
+	actual, err := json.MarshalIndent(calls, "", "  ")
+	require.NoError(t, err)
+	actual = append(actual, '\n')
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		require.NoError(t, os.WriteFile(path, actual, 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err, "golden file missing, run the tests with -update to create it")
+	assert.Equal(t, string(expected), string(actual))
+}
@@ -0,0 +1,146 @@
+package discord
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// HandlerFunc handles an interaction. It returns an error, if it failed to respond to the user.
+type HandlerFunc func(Responder, *discordgo.InteractionCreate) error
+
+// Middleware wraps a handler to add behavior shared by many handlers.
+type Middleware func(HandlerFunc) HandlerFunc
+
+// chain wraps the handler in the given middlewares, the first one being the outermost.
+// Every handler recovers from panics, including those of the middlewares, and logs errors, regardless of the given middlewares.
+func chain(handler HandlerFunc, middlewares ...Middleware) InteractionFunction {
+	for _, middleware := range slices.Backward(middlewares) {
+		handler = middleware(handler)
+	}
+	return WithErrorLogging(WithRecovery(handler))
+}
+
+func WithErrorLogging(f HandlerFunc) InteractionFunction {
+	wrapFunc := func(s Responder, i *discordgo.InteractionCreate) {
+		err := f(s, i)
+		if err != nil {
+			slog.Error("failed to report error to user", append(describeInteraction(i), "error", err)...)
+		}
+	}
+	return wrapFunc
+}
+
+// panicMessage is shown to the user, if the handler panicked.
+const panicMessage = "Something went wrong. Please try again later or contact an admin."
+
+// trackingResponder records how the interaction has been responded to, so a panicking handler's response can be completed.
+type trackingResponder struct {
+	Responder
+	responded    bool
+	responseType discordgo.InteractionResponseType
+}
+
+func (t *trackingResponder) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+	err := t.Responder.InteractionRespond(interaction, resp, options...)
+	if err == nil {
+		t.responded = true
+		t.responseType = resp.Type
+	}
+	return err
+}
+
+// WithRecovery recovers from panics in the handler. The panic is logged together with the interaction and the user is told that something went wrong.
+// How the user is told depends on the interaction and whether the handler has already responded to it: autocomplete interactions get no suggestions,
+// a deferred response's placeholder is replaced and a follow-up message is sent, if the handler has already responded otherwise.
+func WithRecovery(f HandlerFunc) HandlerFunc {
+	return func(s Responder, i *discordgo.InteractionCreate) (err error) {
+		tracker := &trackingResponder{Responder: s}
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			slog.Error("recovered from panic in handler", append(describeInteraction(i), "panic", recovered, "stack", string(debug.Stack()))...)
+			err = respondToPanic(tracker, i)
+		}()
+
+		return f(tracker, i)
+	}
+}
+
+// respondToPanic tells the user that handling the interaction failed, in the way discord accepts given the response sent so far.
+func respondToPanic(t *trackingResponder, i *discordgo.InteractionCreate) error {
+	content := panicMessage
+	switch {
+	case i.Type == discordgo.InteractionApplicationCommandAutocomplete:
+		if t.responded {
+			return nil
+		}
+		return t.Responder.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+			Data: &discordgo.InteractionResponseData{Choices: []*discordgo.ApplicationCommandOptionChoice{}},
+		})
+	case !t.responded:
+		return t.Responder.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: content,
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+	case t.responseType == discordgo.InteractionResponseDeferredChannelMessageWithSource:
+		_, err := t.Responder.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &content})
+		return err
+	default:
+		_, err := t.Responder.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		return err
+	}
+}
+
+// interactionUserID returns the ID of the user, who caused the interaction. It works for interactions in guilds as well as in direct messages.
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+// describeInteraction returns log attributes identifying the interaction, i.e. the user, the command and its options or the custom ID of the component or modal.
+func describeInteraction(i *discordgo.InteractionCreate) []any {
+	attributes := []any{"user", interactionUserID(i), "guild", i.GuildID}
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand, discordgo.InteractionApplicationCommandAutocomplete:
+		commandData := i.ApplicationCommandData()
+		attributes = append(attributes, "command", commandData.Name, "options", formatOptions(commandData.Options))
+	case discordgo.InteractionMessageComponent:
+		attributes = append(attributes, "component", i.MessageComponentData().CustomID)
+	case discordgo.InteractionModalSubmit:
+		attributes = append(attributes, "modal", i.ModalSubmitData().CustomID)
+	}
+	return attributes
+}
+
+// formatOptions formats the options of a command including those of sub commands, e.g. "pack(set_code=IKO count=2)".
+func formatOptions(options []*discordgo.ApplicationCommandInteractionDataOption) string {
+	formatted := make([]string, len(options))
+	for idx, option := range options {
+		if len(option.Options) > 0 || option.Value == nil {
+			formatted[idx] = fmt.Sprintf("%s(%s)", option.Name, formatOptions(option.Options))
+		} else {
+			formatted[idx] = fmt.Sprintf("%s=%v", option.Name, option.Value)
+		}
+	}
+	return strings.Join(formatted, " ")
+}
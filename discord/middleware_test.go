@@ -0,0 +1,91 @@
+package discord
+
+import (
+	"progression/repository"
+	"progression/repository/repositorytest"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRecovery(t *testing.T) {
+	responder := &recordingResponder{}
+	handler := chain(func(s Responder, i *discordgo.InteractionCreate) error {
+		var player *repository.Player
+		_ = player.Id
+		return nil
+	})
+
+	assert.NotPanics(t, func() {
+		handler(responder, newCommandInteraction(testUserID, "balance"))
+	})
+	assertGolden(t, "recovered_panic", responder.calls)
+}
+
+func TestWithRecovery_deferred(t *testing.T) {
+	bot := newTestBot(t, repositorytest.NewMemoryDataStore(), nil)
+	responder := &recordingResponder{}
+	handler := chain(func(s Responder, i *discordgo.InteractionCreate) error {
+		panic("redemption failed")
+	}, bot.WithDeferredResponse)
+
+	assert.NotPanics(t, func() {
+		handler(responder, newCommandInteraction(testUserID, "redeem"))
+	})
+	assertGolden(t, "recovered_panic_deferred", responder.calls)
+}
+
+func TestWithRecovery_autocomplete(t *testing.T) {
+	responder := &recordingResponder{}
+	handler := chain(func(s Responder, i *discordgo.InteractionCreate) error {
+		panic("suggestions failed")
+	})
+
+	interaction := newCommandInteraction(testUserID, "ban", stringOption("card_name", "Lur"))
+	interaction.Type = discordgo.InteractionApplicationCommandAutocomplete
+	assert.NotPanics(t, func() {
+		handler(responder, interaction)
+	})
+	assertGolden(t, "recovered_panic_autocomplete", responder.calls)
+}
+
+func TestWithRecovery_middleware(t *testing.T) {
+	responder := &recordingResponder{}
+	panicking := func(HandlerFunc) HandlerFunc {
+		return func(Responder, *discordgo.InteractionCreate) error {
+			panic("middleware failed")
+		}
+	}
+	handler := chain(func(Responder, *discordgo.InteractionCreate) error { return nil }, panicking)
+
+	assert.NotPanics(t, func() {
+		handler(responder, newCommandInteraction(testUserID, "balance"))
+	})
+	assertGolden(t, "recovered_panic", responder.calls)
+}
+
+func TestCommands_direct_message(t *testing.T) {
+	dataStore := repositorytest.NewMemoryDataStore()
+	require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: testUserID, WildRares: 1}))
+	bot := newTestBot(t, dataStore, nil)
+	responder := &recordingResponder{}
+
+	interaction := newCommandInteraction(testUserID, "balance")
+	interaction.GuildID = ""
+	interaction.User = interaction.Member.User
+	interaction.Member = nil
+
+	assert.NotPanics(t, func() {
+		bot.commandHandlers["balance"](responder, interaction)
+	})
+	assertGolden(t, "balance_direct_message", responder.calls)
+}
+
+func TestFormatOptions(t *testing.T) {
+	options := []*discordgo.ApplicationCommandInteractionDataOption{
+		subCommandOption("pack", stringOption("set_code", "IKO"), intOption("count", 2)),
+	}
+	assert.Equal(t, "pack(set_code=IKO count=2)", formatOptions(options))
+}
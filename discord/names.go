@@ -0,0 +1,25 @@
+package discord
+
+import "log/slog"
+
+// PlayerName returns the user's display name, e.g. to show players outside of discord. It returns the user ID, if the user cannot be looked up.
+// Names are cached, since looking them up requires a request to discord.
+func (b *Bot) PlayerName(userID string) string {
+	if name, found := b.playerNames.Load(userID); found {
+		return name.(string)
+	}
+
+	user, err := b.session.User(userID)
+	if err != nil {
+		slog.Warn("failed to look up user", "error", err, "user", userID)
+		return userID
+	}
+
+	name := user.GlobalName
+	if name == "" {
+		name = user.Username
+	}
+
+	b.playerNames.Store(userID, name)
+	return name
+}
@@ -0,0 +1,38 @@
+package discord
+
+import (
+	"context"
+	"log/slog"
+	"progression/league"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// RoleCapabilities maps guild IDs to the names of roles in the guild and the capabilities they grant.
+type RoleCapabilities map[string]map[string][]league.Capability
+
+// adminPermissions are the permissions required to see admin commands by default.
+// Server admins can make them visible to further roles, e.g. those granting capabilities, in the server's integration settings.
+var adminPermissions int64 = discordgo.PermissionManageGuild
+
+// authorizedContext returns a context granting the capabilities of the member's roles in the guild the interaction happened in.
+// Users without any of these roles fall back to the league's admin table.
+func (b *Bot) authorizedContext(s Responder, i *discordgo.InteractionCreate) context.Context {
+	ctx := context.Background()
+	roleCapabilities := b.roleCapabilities[i.GuildID]
+	if i.Member == nil || len(roleCapabilities) == 0 {
+		return ctx
+	}
+
+	var capabilities []league.Capability
+	for _, roleID := range i.Member.Roles {
+		role, err := s.Role(i.GuildID, roleID)
+		if err != nil {
+			slog.Warn("failed to look up role", "error", err, "guild", i.GuildID, "role", roleID)
+			continue
+		}
+		capabilities = append(capabilities, roleCapabilities[role.Name]...)
+	}
+
+	return league.WithCapabilities(ctx, capabilities)
+}
@@ -0,0 +1,93 @@
+package discord
+
+import (
+	"encoding/json"
+	"log/slog"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// registerCommands registers the bot's commands in every configured guild or, if no guild is configured, globally.
+// Discord is only called, if the registered commands differ from the bot's commands. Commands are never removed on shutdown.
+func (b *Bot) registerCommands() error {
+	guildIDs := b.guildIDs
+	if len(guildIDs) == 0 {
+		guildIDs = []string{""}
+	}
+
+	appID := b.session.State.User.ID
+	for _, guildID := range guildIDs {
+		registered, err := b.session.ApplicationCommands(appID, guildID)
+		if err != nil {
+			slog.Error("Cannot fetch registered commands", "guild", guildID, "error", err)
+			return err
+		}
+
+		changed := changedCommands(b.commands, registered)
+		if len(changed) == 0 {
+			slog.Info("Commands are up to date.", "guild", guildID)
+			continue
+		}
+
+		slog.Info("Updating commands...", "guild", guildID, "commands", changed)
+		_, err = b.session.ApplicationCommandBulkOverwrite(appID, guildID, b.commands)
+		if err != nil {
+			slog.Error("Cannot update commands", "guild", guildID, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// changedCommands returns the names of all commands, which have been added, changed or removed compared to the registered commands.
+func changedCommands(commands []*discordgo.ApplicationCommand, registered []*discordgo.ApplicationCommand) []string {
+	registeredSignatures := make(map[string]string, len(registered))
+	for _, command := range registered {
+		registeredSignatures[command.Name], _ = commandSignature(command)
+	}
+
+	var changed []string
+	for _, command := range commands {
+		registeredSignature, found := registeredSignatures[command.Name]
+		signature, err := commandSignature(command)
+		if !found || err != nil || signature != registeredSignature {
+			changed = append(changed, command.Name)
+		}
+		delete(registeredSignatures, command.Name)
+	}
+
+	for name := range registeredSignatures {
+		changed = append(changed, name)
+	}
+
+	slices.Sort(changed)
+	return changed
+}
+
+// commandSignature serializes the parts of a command defined by the bot, ignoring everything discord adds when registering it.
+func commandSignature(command *discordgo.ApplicationCommand) (string, error) {
+	commandType := command.Type
+	if commandType == 0 {
+		commandType = discordgo.ChatApplicationCommand
+	}
+
+	signature, err := json.Marshal(struct {
+		Type                     discordgo.ApplicationCommandType
+		Name                     string
+		Description              string
+		DefaultMemberPermissions *int64
+		Options                  []*discordgo.ApplicationCommandOption
+	}{
+		Type:                     commandType,
+		Name:                     command.Name,
+		Description:              command.Description,
+		DefaultMemberPermissions: command.DefaultMemberPermissions,
+		Options:                  command.Options,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(signature), nil
+}
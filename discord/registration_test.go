@@ -0,0 +1,89 @@
+package discord
+
+import (
+	"progression/repository/repositorytest"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangedCommands(t *testing.T) {
+	command := func(name, description string, options ...*discordgo.ApplicationCommandOption) *discordgo.ApplicationCommand {
+		return &discordgo.ApplicationCommand{Name: name, Description: description, Options: options}
+	}
+	// registered returns the command like discord returns it, including the fields discord adds when registering it.
+	registered := func(command *discordgo.ApplicationCommand) *discordgo.ApplicationCommand {
+		registered := *command
+		registered.ID = "id-" + command.Name
+		registered.ApplicationID = "application"
+		registered.Version = "1"
+		registered.Type = discordgo.ChatApplicationCommand
+		return &registered
+	}
+	setCode := &discordgo.ApplicationCommandOption{Type: discordgo.ApplicationCommandOptionString, Name: "set_code", Description: "The set.", Required: true}
+	autocompletedSetCode := *setCode
+	autocompletedSetCode.Autocomplete = true
+
+	tests := []struct {
+		name       string
+		commands   []*discordgo.ApplicationCommand
+		registered []*discordgo.ApplicationCommand
+		expected   []string
+	}{
+		{
+			name:       "identical",
+			commands:   []*discordgo.ApplicationCommand{command("join", "Join the league."), command("start", "Start a league.", setCode)},
+			registered: []*discordgo.ApplicationCommand{registered(command("start", "Start a league.", setCode)), registered(command("join", "Join the league."))},
+		},
+		{
+			name:       "added",
+			commands:   []*discordgo.ApplicationCommand{command("join", "Join the league."), command("drop", "Drop from the league.")},
+			registered: []*discordgo.ApplicationCommand{registered(command("join", "Join the league."))},
+			expected:   []string{"drop"},
+		},
+		{
+			name:       "removed",
+			commands:   []*discordgo.ApplicationCommand{command("join", "Join the league.")},
+			registered: []*discordgo.ApplicationCommand{registered(command("join", "Join the league.")), registered(command("drop", "Drop from the league."))},
+			expected:   []string{"drop"},
+		},
+		{
+			name:       "changed_description",
+			commands:   []*discordgo.ApplicationCommand{command("join", "Join the current league.")},
+			registered: []*discordgo.ApplicationCommand{registered(command("join", "Join the league."))},
+			expected:   []string{"join"},
+		},
+		{
+			name:       "changed_option",
+			commands:   []*discordgo.ApplicationCommand{command("start", "Start a league.", &autocompletedSetCode)},
+			registered: []*discordgo.ApplicationCommand{registered(command("start", "Start a league.", setCode))},
+			expected:   []string{"start"},
+		},
+		{
+			name:     "nothing_registered",
+			commands: []*discordgo.ApplicationCommand{command("join", "Join the league."), command("drop", "Drop from the league.")},
+			expected: []string{"drop", "join"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, changedCommands(tt.commands, tt.registered))
+		})
+	}
+}
+
+func TestChangedCommands_bot_commands(t *testing.T) {
+	bot := newTestBot(t, repositorytest.NewMemoryDataStore(), nil)
+	registered := make([]*discordgo.ApplicationCommand, 0, len(bot.commands))
+	for _, command := range bot.commands {
+		// discord returns the commands with the fields it adds when registering them
+		registeredCommand := *command
+		registeredCommand.ID = "id-" + command.Name
+		registeredCommand.Version = "1"
+		registered = append(registered, &registeredCommand)
+	}
+
+	assert.Empty(t, changedCommands(bot.commands, registered), "expected the registered commands to be up to date")
+}
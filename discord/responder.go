@@ -0,0 +1,26 @@
+package discord
+
+import "github.com/bwmarrin/discordgo"
+
+// Responder is the part of discord's API the handlers use to respond to interactions.
+// It is implemented by a discord session and can be replaced by a fake in tests.
+type Responder interface {
+	InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error
+	InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	InteractionResponseDelete(interaction *discordgo.Interaction, options ...discordgo.RequestOption) error
+	FollowupMessageCreate(interaction *discordgo.Interaction, wait bool, data *discordgo.WebhookParams, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	// Role returns the role with the given ID in the guild.
+	Role(guildID, roleID string) (*discordgo.Role, error)
+}
+
+// sessionResponder responds to interactions using a discord session.
+type sessionResponder struct {
+	*discordgo.Session
+}
+
+// Role looks up the role in the session's state, which caches the roles of all guilds the bot is part of.
+func (s sessionResponder) Role(guildID, roleID string) (*discordgo.Role, error) {
+	return s.State.Role(guildID, roleID)
+}
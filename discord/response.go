@@ -0,0 +1,262 @@
+package discord
+
+import (
+	"fmt"
+	"log/slog"
+	"progression/league"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// progressInterval limits how often the progress of slow operations is shown, keeping clear of discord's rate limits.
+const progressInterval = 2 * time.Second
+
+// Visibility defines who can see the response to an interaction.
+type Visibility int
+
+const (
+	// VisibilityPublic shows the response to everyone in the channel.
+	VisibilityPublic Visibility = iota
+	// VisibilityEphemeral shows the response only to the user of the interaction.
+	VisibilityEphemeral
+	// VisibilityDirectMessage sends the response to the user in a direct message. The channel only receives a hint only the user can see.
+	VisibilityDirectMessage
+)
+
+// generateCommandVisibilityMap defines the visibility of responses to each command. Commands not listed respond publicly.
+// Personal information is kept private, while everything concerning the whole league, e.g. starting a round, is public.
+func generateCommandVisibilityMap() map[string]Visibility {
+	commandVisibilities := map[string]Visibility{
+		"help":    VisibilityEphemeral,
+		"pool":    VisibilityDirectMessage,
+		"balance": VisibilityEphemeral,
+		"ledger":  VisibilityEphemeral,
+		"admin":   VisibilityEphemeral,
+		// Opened packs are only revealed to everyone on request.
+		"redeem pack": VisibilityEphemeral,
+	}
+	return commandVisibilities
+}
+
+// deferral records how the response to an interaction has been deferred.
+type deferral struct {
+	// ephemeral is set, if only the user can see the placeholder.
+	ephemeral bool
+	// update is set, if the message containing the component used will be updated instead of sending a new one.
+	update bool
+}
+
+// visibility returns the visibility of responses to the interaction as defined for its command or sub command.
+// A command's "public" option overrides the visibility, if it is set.
+// Responses to message components and modals are public, since they continue a conversation in the channel.
+func (b *Bot) visibility(i *discordgo.InteractionCreate) Visibility {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return VisibilityPublic
+	}
+
+	commandData := i.ApplicationCommandData()
+	visibility := b.commandVisibilities[commandData.Name]
+	options := commandData.Options
+	if len(options) > 0 && options[0].Type == discordgo.ApplicationCommandOptionSubCommand {
+		if subCommandVisibility, found := b.commandVisibilities[commandData.Name+" "+options[0].Name]; found {
+			visibility = subCommandVisibility
+		}
+		options = options[0].Options
+	}
+
+	for _, option := range options {
+		if option.Name == "public" && option.Type == discordgo.ApplicationCommandOptionBoolean && option.BoolValue() {
+			return VisibilityPublic
+		}
+	}
+
+	return visibility
+}
+
+// WithDeferredResponse acknowledges the interaction right away, so the handler can take longer than discord's deadline of 3 seconds to respond.
+// Responses sent by the handler replace the placeholder shown in the meantime.
+func (b *Bot) WithDeferredResponse(f HandlerFunc) HandlerFunc {
+	return func(s Responder, i *discordgo.InteractionCreate) error {
+		d := deferral{
+			ephemeral: b.visibility(i) != VisibilityPublic,
+			update:    i.Type == discordgo.InteractionMessageComponent,
+		}
+
+		response := &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		}
+		switch {
+		case d.update:
+			response.Type = discordgo.InteractionResponseDeferredMessageUpdate
+		case d.ephemeral:
+			response.Data = &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral}
+		}
+
+		err := s.InteractionRespond(i.Interaction, response)
+		if err != nil {
+			return err
+		}
+
+		b.deferrals.Store(i.ID, d)
+		defer b.deferrals.Delete(i.ID)
+
+		return f(s, i)
+	}
+}
+
+// SendProgress replaces the placeholder of a deferred response with the given message. It does nothing, if the response hasn't been deferred.
+func (b *Bot) SendProgress(s Responder, i *discordgo.InteractionCreate, msg string) error {
+	if _, deferred := b.deferrals.Load(i.ID); !deferred {
+		return nil
+	}
+
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &msg,
+	})
+	return err
+}
+
+// progressReporter returns a function showing the progress of a slow operation as the placeholder of a deferred response.
+// The format receives the number of finished and total steps. Updates are sent at most once per progressInterval.
+func (b *Bot) progressReporter(s Responder, i *discordgo.InteractionCreate, format string) league.ProgressFunc {
+	var lastUpdate time.Time
+	return func(done, total int) {
+		if time.Since(lastUpdate) < progressInterval {
+			return
+		}
+		lastUpdate = time.Now()
+
+		err := b.SendProgress(s, i, fmt.Sprintf(format, done, total))
+		if err != nil {
+			slog.Warn("failed to show progress", "error", err, "user", interactionUserID(i))
+		}
+	}
+}
+
+// respond sends the response data with the given visibility. Embeds exceeding discord's limits are split into as many messages as necessary.
+// If the user doesn't accept direct messages, the response is sent ephemerally instead.
+func (b *Bot) respond(s Responder, i *discordgo.InteractionCreate, visibility Visibility, data *discordgo.InteractionResponseData) error {
+	if visibility == VisibilityDirectMessage {
+		err := b.sendDirectMessage(s, interactionUserID(i), data)
+		if err == nil {
+			return b.respond(s, i, VisibilityEphemeral, &discordgo.InteractionResponseData{
+				Content: "I've sent you a direct message.",
+			})
+		}
+
+		slog.Warn("failed to send direct message, responding ephemerally instead", "error", err, "user", interactionUserID(i))
+		visibility = VisibilityEphemeral
+	}
+
+	if visibility == VisibilityEphemeral {
+		data.Flags |= discordgo.MessageFlagsEphemeral
+	}
+
+	chunks := chunkEmbeds(data.Embeds)
+	if len(chunks) > 0 {
+		data.Embeds, chunks = chunks[0], chunks[1:]
+	}
+
+	err := b.sendFirstResponse(s, i, data)
+	if err != nil {
+		return err
+	}
+
+	for _, chunk := range chunks {
+		_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Embeds: chunk,
+			Flags:  data.Flags,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendFirstResponse responds to the interaction or, if the response has been deferred, replaces the placeholder.
+// A placeholder visible to everyone is deleted in favor of a new message, if only the user is supposed to see the response.
+func (b *Bot) sendFirstResponse(s Responder, i *discordgo.InteractionCreate, data *discordgo.InteractionResponseData) error {
+	value, deferred := b.deferrals.Load(i.ID)
+	if !deferred {
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: data,
+		})
+	}
+
+	d := value.(deferral)
+	ephemeral := data.Flags&discordgo.MessageFlagsEphemeral != 0
+	if d.update || (ephemeral && !d.ephemeral) {
+		if !d.update {
+			err := s.InteractionResponseDelete(i.Interaction)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content:    data.Content,
+			Components: data.Components,
+			Embeds:     data.Embeds,
+			Files:      data.Files,
+			Flags:      data.Flags,
+		})
+		return err
+	}
+
+	edit := &discordgo.WebhookEdit{
+		Content: &data.Content,
+		Files:   data.Files,
+	}
+	if data.Embeds != nil {
+		edit.Embeds = &data.Embeds
+	}
+	if data.Components != nil {
+		edit.Components = &data.Components
+	}
+
+	_, err := s.InteractionResponseEdit(i.Interaction, edit)
+	return err
+}
+
+// SendDirectMessage sends the message and embeds to the user in a direct message, independent of any interaction.
+func (b *Bot) SendDirectMessage(s Responder, userID, msg string, embeds []*discordgo.MessageEmbed) error {
+	return b.sendDirectMessage(s, userID, &discordgo.InteractionResponseData{
+		Content: msg,
+		Embeds:  embeds,
+	})
+}
+
+// sendDirectMessage sends the response data to the user in a direct message, split into as many messages as necessary.
+func (b *Bot) sendDirectMessage(s Responder, userID string, data *discordgo.InteractionResponseData) error {
+	channel, err := s.UserChannelCreate(userID)
+	if err != nil {
+		return err
+	}
+
+	chunks := chunkEmbeds(data.Embeds)
+	if len(chunks) == 0 {
+		chunks = append(chunks, nil)
+	}
+
+	for idx, chunk := range chunks {
+		message := &discordgo.MessageSend{
+			Embeds: chunk,
+		}
+		if idx == 0 {
+			message.Content = data.Content
+			message.Components = data.Components
+			message.Files = data.Files
+		}
+
+		_, err = s.ChannelMessageSendComplex(channel.ID, message)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package discord
+
+import (
+	"fmt"
+	"net/url"
+	"progression/league"
+	"progression/repository"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord's limits for embeds sent in a single message.
+const (
+	maxEmbedsPerMessage     = 10
+	maxEmbedCharsPerMessage = 6000
+	maxFieldValueLength     = 1024
+)
+
+// rarityColors are the colors of an embed revealing a pack, depending on the pack's highest rarity.
+var rarityColors = map[repository.Rarity]int{
+	repository.RarityCommon:   0x2B2D31,
+	repository.RarityUncommon: 0x9FA8B0,
+	repository.RarityRare:     0xC9A227,
+	repository.RarityMythic:   0xD35400,
+}
+
+// generatePackEmbeds renders every pack opened by the player as an embed with a field per rarity slot, from the highest to the lowest rarity.
+func generatePackEmbeds(userID string, packs []league.Pack) []*discordgo.MessageEmbed {
+	embeds := make([]*discordgo.MessageEmbed, 0, len(packs))
+	for idx, pack := range packs {
+		embed := &discordgo.MessageEmbed{
+			Title:       fmt.Sprintf("[%s] Pack %d/%d", pack.Set, idx+1, len(packs)),
+			Description: fmt.Sprintf("Opened by <@%s>", userID),
+		}
+
+		for _, rarity := range slices.Backward(repository.Rarities) {
+			cards := pack.Slots[rarity]
+			if len(cards) == 0 {
+				continue
+			}
+
+			if embed.Color == 0 {
+				embed.Color = rarityColors[rarity]
+			}
+
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:  strings.ToUpper(string(rarity[:1])) + string(rarity[1:]),
+				Value: formatRaritySlot(cards),
+			})
+		}
+
+		embeds = append(embeds, embed)
+	}
+	return embeds
+}
+
+// formatRaritySlot lists the cards of a rarity slot. The links are left out, if the list would exceed discord's limit otherwise.
+func formatRaritySlot(cards []repository.Card) string {
+	lines := make([]string, len(cards))
+	for i, card := range cards {
+		lines[i] = formatRevealedCard(card, true)
+	}
+
+	value := strings.Join(lines, "\n")
+	if len([]rune(value)) <= maxFieldValueLength {
+		return value
+	}
+
+	for i, card := range cards {
+		lines[i] = formatRevealedCard(card, false)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatRevealedCard optionally links the card to scryfall and marks foils.
+func formatRevealedCard(card repository.Card, withLink bool) string {
+	line := card.Name
+	if link := trimQuery(card.ScryfallURI); withLink && link != "" {
+		line = fmt.Sprintf("[%s](%s)", card.Name, link)
+	}
+
+	if card.Foil {
+		line += " ✨"
+	}
+	return line
+}
+
+// trimQuery removes the tracking parameters scryfall appends to its links, since the length of embeds is limited.
+func trimQuery(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+
+	parsed.RawQuery = ""
+	return parsed.String()
+}
+
+// chunkEmbeds splits the embeds into groups, which can be sent in a single message each.
+func chunkEmbeds(embeds []*discordgo.MessageEmbed) [][]*discordgo.MessageEmbed {
+	var chunks [][]*discordgo.MessageEmbed
+	var chunk []*discordgo.MessageEmbed
+	chars := 0
+	for _, embed := range embeds {
+		size := embedSize(embed)
+		if len(chunk) == maxEmbedsPerMessage || (len(chunk) > 0 && chars+size > maxEmbedCharsPerMessage) {
+			chunks = append(chunks, chunk)
+			chunk = nil
+			chars = 0
+		}
+
+		chunk = append(chunk, embed)
+		chars += size
+	}
+
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// embedSize counts the characters of the embed, which count towards discord's limit per message.
+func embedSize(embed *discordgo.MessageEmbed) int {
+	size := len([]rune(embed.Title)) + len([]rune(embed.Description))
+	for _, field := range embed.Fields {
+		size += len([]rune(field.Name)) + len([]rune(field.Value))
+	}
+	return size
+}
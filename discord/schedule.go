@@ -0,0 +1,59 @@
+package discord
+
+import (
+	"fmt"
+	"log/slog"
+	"progression/league"
+	"progression/repository"
+	"strings"
+	"time"
+)
+
+// remindPlayers sends every player of the unreported pairings a direct message and posts a countdown in the announcement channel.
+func (b *Bot) remindPlayers(s Responder, event league.DeadlineReminder) {
+	for _, pairing := range event.Pairings {
+		for _, players := range [][2]string{{pairing.Player1, pairing.Player2}, {pairing.Player2, pairing.Player1}} {
+			message := fmt.Sprintf("Your match against <@%s> hasn't been reported yet. Use /report by %s.", players[1], formatTimestamp(event.Deadline))
+			err := b.SendDirectMessage(s, players[0], message, nil)
+			if err != nil {
+				slog.Warn("failed to send reminder in a direct message", "error", err, "user", players[0], "round", event.Round)
+			}
+		}
+	}
+
+	message := fmt.Sprintf("The round ends %s. These matches haven't been reported yet:\n%s",
+		formatRelativeTimestamp(event.Deadline), formatPairings(event.Pairings))
+	b.sendAnnouncement(s, message)
+}
+
+// formatDeadlinePassed describes how the unreported pairings have been resolved at the deadline.
+func formatDeadlinePassed(event league.DeadlinePassed) string {
+	switch event.Policy {
+	case league.DeadlinePolicyDraw:
+		return "The round's deadline has passed. These matches have been reported as draws:\n" + formatPairings(event.Pairings)
+	default:
+		return "The round's deadline has passed. These matches have been flagged for the admins, who can report them using /report with a user:\n" +
+			formatPairings(event.Pairings)
+	}
+}
+
+func formatPairings(pairings []repository.Pairing) string {
+	var builder strings.Builder
+	for idx, pairing := range pairings {
+		if idx > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(fmt.Sprintf("- <@%s> vs <@%s>", pairing.Player1, pairing.Player2))
+	}
+	return builder.String()
+}
+
+// formatTimestamp formats the time, so discord shows it in the reader's timezone, e.g. "1 June 2025 14:00".
+func formatTimestamp(t time.Time) string {
+	return fmt.Sprintf("<t:%d:f>", t.Unix())
+}
+
+// formatRelativeTimestamp formats the time, so discord shows it relative to the reader's current time, e.g. "in 2 days".
+func formatRelativeTimestamp(t time.Time) string {
+	return fmt.Sprintf("<t:%d:R>", t.Unix())
+}
@@ -0,0 +1,333 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"progression/league"
+	"progression/repository"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// The custom ID prefixes of the buttons attached to a trade offer. They are followed by the ID of the trade.
+const (
+	tradeAcceptComponentID  = "trade_accept"
+	tradeCounterComponentID = "trade_counter"
+	tradeDeclineComponentID = "trade_decline"
+)
+
+// tradeItemsFormat explains the format of the items in a trade offer to the user.
+const tradeItemsFormat = "Separate items by commas. Use `<count> <set_code> <collector_number> [foil]` for cards, `<count> <rarity>` for wild cards and `<count> pack` for wild packs, e.g. `2 IKO 1, 1 rare, 3 pack`."
+
+var errInvalidTradeItem = errors.New("invalid trade item")
+
+func (b *Bot) TradeCommand(s Responder, i *discordgo.InteractionCreate) error {
+	proposerID := interactionUserID(i)
+	subCommand := i.ApplicationCommandData().Options[0]
+	recipientID := subCommand.GetOption("user").UserValue(nil).ID
+
+	var give, get string
+	if option := subCommand.GetOption("give"); option != nil {
+		give = option.StringValue()
+	}
+	if option := subCommand.GetOption("get"); option != nil {
+		get = option.StringValue()
+	}
+
+	items, err := parseTradeOffer(proposerID, recipientID, give, get)
+	if err != nil {
+		return b.SendError(s, i, formatTradeError(err))
+	}
+
+	trade, err := b.leagueManager.OfferTrade(context.Background(), proposerID, recipientID, items)
+	if err != nil {
+		return b.SendError(s, i, formatTradeError(err))
+	}
+
+	return b.SendComponents(s, i, formatTradeOffer(trade), generateTradeButtons(trade.Id))
+}
+
+func (b *Bot) TradeAcceptComponent(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
+	tradeID, err := parseTradeID(i.MessageComponentData().CustomID)
+	if err != nil {
+		return b.SendError(s, i, formatTradeError(err))
+	}
+
+	trade, err := b.leagueManager.AcceptTrade(userID, tradeID)
+	if err != nil {
+		return b.SendError(s, i, formatTradeError(err))
+	}
+
+	return b.UpdateMessage(s, i, fmt.Sprintf("%s\n<@%s> accepted the trade.", formatTradeOffer(trade), userID))
+}
+
+func (b *Bot) TradeDeclineComponent(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
+	tradeID, err := parseTradeID(i.MessageComponentData().CustomID)
+	if err != nil {
+		return b.SendError(s, i, formatTradeError(err))
+	}
+
+	trade, err := b.leagueManager.DeclineTrade(userID, tradeID)
+	if err != nil {
+		return b.SendError(s, i, formatTradeError(err))
+	}
+
+	return b.UpdateMessage(s, i, fmt.Sprintf("%s\n<@%s> declined the trade.", formatTradeOffer(trade), userID))
+}
+
+// TradeCounterComponent opens a form, prefilled with the current offer from the perspective of the recipient, to make a counter offer.
+func (b *Bot) TradeCounterComponent(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
+	customID := i.MessageComponentData().CustomID
+	tradeID, err := parseTradeID(customID)
+	if err != nil {
+		return b.SendError(s, i, formatTradeError(err))
+	}
+
+	trade, err := b.leagueManager.GetTrade(tradeID)
+	if err != nil {
+		return b.SendError(s, i, formatTradeError(err))
+	}
+
+	if trade.Recipient != userID {
+		return b.SendError(s, i, formatTradeError(league.ErrNotTradeRecipient))
+	}
+
+	var give, get []string
+	for _, item := range trade.Items {
+		if item.FromPlayer == userID {
+			give = append(give, formatTradeItemInput(item))
+		} else {
+			get = append(get, formatTradeItemInput(item))
+		}
+	}
+
+	return b.SendModal(s, i, customID, "Counter offer", []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.TextInput{
+				CustomID: "give",
+				Label:    "You give",
+				Style:    discordgo.TextInputParagraph,
+				Value:    strings.Join(give, ", "),
+			},
+		}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.TextInput{
+				CustomID: "get",
+				Label:    "You get",
+				Style:    discordgo.TextInputParagraph,
+				Value:    strings.Join(get, ", "),
+			},
+		}},
+	})
+}
+
+func (b *Bot) TradeCounterModal(s Responder, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
+	modalData := i.ModalSubmitData()
+	tradeID, err := parseTradeID(modalData.CustomID)
+	if err != nil {
+		return b.SendError(s, i, formatTradeError(err))
+	}
+
+	trade, err := b.leagueManager.GetTrade(tradeID)
+	if err != nil {
+		return b.SendError(s, i, formatTradeError(err))
+	}
+
+	inputs := textInputValues(modalData.Components)
+	items, err := parseTradeOffer(userID, trade.Proposer, inputs["give"], inputs["get"])
+	if err != nil {
+		return b.SendError(s, i, formatTradeError(err))
+	}
+
+	counterTrade, err := b.leagueManager.CounterTrade(context.Background(), userID, tradeID, items)
+	if err != nil {
+		return b.SendError(s, i, formatTradeError(err))
+	}
+
+	return b.SendComponents(s, i, formatTradeOffer(counterTrade), generateTradeButtons(counterTrade.Id))
+}
+
+func generateTradeButtons(tradeID int) []discordgo.MessageComponent {
+	id := strconv.Itoa(tradeID)
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Accept",
+					Style:    discordgo.SuccessButton,
+					CustomID: tradeAcceptComponentID + ":" + id,
+				},
+				discordgo.Button{
+					Label:    "Counter",
+					Style:    discordgo.SecondaryButton,
+					CustomID: tradeCounterComponentID + ":" + id,
+				},
+				discordgo.Button{
+					Label:    "Decline",
+					Style:    discordgo.DangerButton,
+					CustomID: tradeDeclineComponentID + ":" + id,
+				},
+			},
+		},
+	}
+}
+
+// textInputValues returns the values of all text inputs in a submitted modal by their custom ID.
+func textInputValues(components []discordgo.MessageComponent) map[string]string {
+	values := make(map[string]string)
+	for _, component := range components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+
+		for _, rowComponent := range row.Components {
+			if input, ok := rowComponent.(*discordgo.TextInput); ok {
+				values[input.CustomID] = input.Value
+			}
+		}
+	}
+	return values
+}
+
+func parseTradeID(customID string) (int, error) {
+	_, id, _ := strings.Cut(customID, ":")
+	return strconv.Atoi(id)
+}
+
+// parseTradeOffer parses the items the proposer gives and gets in the format explained by tradeItemsFormat.
+func parseTradeOffer(proposerID, recipientID, give, get string) ([]repository.TradeItem, error) {
+	giveItems, err := parseTradeItems(proposerID, give)
+	if err != nil {
+		return nil, err
+	}
+
+	getItems, err := parseTradeItems(recipientID, get)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(giveItems, getItems...), nil
+}
+
+// parseTradeItems parses items given by the given player in the format explained by tradeItemsFormat.
+func parseTradeItems(fromPlayer, text string) ([]repository.TradeItem, error) {
+	var items []repository.TradeItem
+	for _, entry := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '\n' }) {
+		fields := strings.Fields(entry)
+		if len(fields) < 2 || len(fields) > 4 {
+			return nil, fmt.Errorf("%w: %q", errInvalidTradeItem, entry)
+		}
+
+		count, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", errInvalidTradeItem, entry)
+		}
+
+		item := repository.TradeItem{
+			FromPlayer: fromPlayer,
+			Count:      count,
+		}
+
+		switch {
+		case len(fields) == 2 && strings.EqualFold(strings.TrimSuffix(fields[1], "s"), string(repository.CurrencyWildPack)):
+			item.Currency = repository.CurrencyWildPack
+		case len(fields) == 2 && slices.Contains(repository.Rarities, repository.Rarity(strings.ToLower(fields[1]))):
+			item.Currency = repository.WildCardCurrency(repository.Rarity(strings.ToLower(fields[1])))
+		case len(fields) == 3:
+			item.Set = strings.ToUpper(fields[1])
+			item.CollectorNumber = fields[2]
+		case len(fields) == 4 && strings.EqualFold(fields[3], "foil"):
+			item.Set = strings.ToUpper(fields[1])
+			item.CollectorNumber = fields[2]
+			item.Foil = true
+		default:
+			return nil, fmt.Errorf("%w: %q", errInvalidTradeItem, entry)
+		}
+
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// formatTradeItemInput formats the item in the format explained by tradeItemsFormat.
+func formatTradeItemInput(item repository.TradeItem) string {
+	if !item.IsCard() {
+		return fmt.Sprintf("%d %s", item.Count, item.Currency)
+	}
+
+	input := fmt.Sprintf("%d %s %s", item.Count, item.Set, item.CollectorNumber)
+	if item.Foil {
+		input += " foil"
+	}
+	return input
+}
+
+func formatTradeItem(item repository.TradeItem) string {
+	switch {
+	case item.Currency == repository.CurrencyWildPack:
+		return fmt.Sprintf("%d wild packs", item.Count)
+	case !item.IsCard():
+		return fmt.Sprintf("%d %s wild cards", item.Count, item.Currency)
+	case item.Foil:
+		return fmt.Sprintf("%d %s (%s #%s, foil)", item.Count, item.Name, item.Set, item.CollectorNumber)
+	default:
+		return fmt.Sprintf("%d %s (%s #%s)", item.Count, item.Name, item.Set, item.CollectorNumber)
+	}
+}
+
+func formatTradeOffer(trade repository.Trade) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("<@%s> offers <@%s> a trade (#%d):\n", trade.Proposer, trade.Recipient, trade.Id))
+	for _, participant := range []string{trade.Proposer, trade.Recipient} {
+		builder.WriteString(fmt.Sprintf("<@%s> gives:\n", participant))
+		given := 0
+		for _, item := range trade.Items {
+			if item.FromPlayer == participant {
+				builder.WriteString("- " + formatTradeItem(item) + "\n")
+				given++
+			}
+		}
+		if given == 0 {
+			builder.WriteString("- nothing\n")
+		}
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+func formatTradeError(err error) string {
+	switch {
+	case errors.Is(err, errInvalidTradeItem):
+		return fmt.Sprintf("%s\n%s", err.Error(), tradeItemsFormat)
+	case errors.Is(err, league.ErrInvalidTrade):
+		return "The trade is invalid. You can't trade with yourself and have to offer or ask for at least one item.\n" + tradeItemsFormat
+	case errors.Is(err, repository.ErrPlayerNotFound):
+		return "Both players have to be part of the current league."
+	case errors.Is(err, league.ErrPlayerAlreadyDropped):
+		return "Both players have to be part of the current league."
+	case errors.Is(err, league.ErrRarityNotTradeable):
+		return "The league's rules don't allow trading this rarity: " + err.Error()
+	case errors.Is(err, league.ErrTradeLimitReached):
+		return "One of the players has already reached the maximum number of trades this round."
+	case errors.Is(err, league.ErrInsufficientWildCards), errors.Is(err, league.ErrInsufficientWildPacks):
+		return "One of the players doesn't have enough wild cards or packs."
+	case errors.Is(err, repository.ErrCardNotInPool):
+		return "One of the players doesn't own enough copies of a card."
+	case errors.Is(err, league.ErrCardNotFound):
+		return "One of the cards does not exist."
+	case errors.Is(err, league.ErrNotTradeRecipient):
+		return "This trade wasn't offered to you."
+	case errors.Is(err, league.ErrTradeNotPending), errors.Is(err, repository.ErrTradeNotFound):
+		return "This trade is no longer available."
+	default:
+		return "Error trading: " + err.Error()
+	}
+}
@@ -0,0 +1,116 @@
+// Package envconfig reads the configuration shared by the bot and the admin CLI from environment variables.
+// Invalid values cause a panic, since neither can run without a valid configuration.
+package envconfig
+
+import (
+	"fmt"
+	"os"
+	"progression/league"
+	"progression/repository"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DataStore returns a datastore for the Postgres database given by PG_HOSTNAME, PG_PORT, PG_USERNAME, PG_PASSWORD and PG_DATABASE.
+// It still has to be connected.
+func DataStore() repository.DataStore {
+	return repository.NewPostgresDataStore(
+		os.Getenv("PG_HOSTNAME"),
+		parseInt("PG_PORT", os.Getenv("PG_PORT")),
+		os.Getenv("PG_USERNAME"),
+		os.Getenv("PG_PASSWORD"),
+		os.Getenv("PG_DATABASE"),
+	)
+}
+
+// LeagueConfig returns the default rules of the league, overridden by the environment variables described in the README.
+func LeagueConfig() league.Config {
+	config := league.DefaultConfig()
+	if rewards := os.Getenv("ROUND_REWARD_WILD_CARDS"); rewards != "" {
+		config.RoundRewardWildCards = parseRarityCounts("ROUND_REWARD_WILD_CARDS", rewards)
+	}
+	if lossRewards := os.Getenv("LOSS_REWARD_WILD_PACKS"); lossRewards != "" {
+		config.LossRewardWildPacks = parseInt("LOSS_REWARD_WILD_PACKS", lossRewards)
+	}
+	if vaultEnabled := os.Getenv("VAULT_ENABLED"); vaultEnabled != "" {
+		enabled, err := strconv.ParseBool(vaultEnabled)
+		if err != nil {
+			panic(fmt.Sprintf("VAULT_ENABLED environment variable not set to a valid value: %v", err))
+		}
+		config.VaultEnabled = enabled
+	}
+	if progress := os.Getenv("VAULT_PROGRESS_PER_COPY"); progress != "" {
+		config.VaultProgressPerCopy = parseRarityCounts("VAULT_PROGRESS_PER_COPY", progress)
+	}
+	if vaultSize := os.Getenv("VAULT_SIZE"); vaultSize != "" {
+		config.VaultSize = parseInt("VAULT_SIZE", vaultSize)
+	}
+	if vaultRewards := os.Getenv("VAULT_REWARD_WILD_CARDS"); vaultRewards != "" {
+		config.VaultRewardWildCards = parseRarityCounts("VAULT_REWARD_WILD_CARDS", vaultRewards)
+	}
+	if untradeable := os.Getenv("UNTRADEABLE_RARITIES"); untradeable != "" {
+		config.UntradeableRarities = parseRarities("UNTRADEABLE_RARITIES", untradeable)
+	}
+	if maxTrades := os.Getenv("MAX_TRADES_PER_ROUND"); maxTrades != "" {
+		config.MaxTradesPerRound = parseInt("MAX_TRADES_PER_ROUND", maxTrades)
+	}
+	if roundDuration := os.Getenv("ROUND_DURATION"); roundDuration != "" {
+		config.RoundDuration = parseDuration("ROUND_DURATION", roundDuration)
+	}
+	if reminderInterval := os.Getenv("REMINDER_INTERVAL"); reminderInterval != "" {
+		config.ReminderInterval = parseDuration("REMINDER_INTERVAL", reminderInterval)
+	}
+	if policy := os.Getenv("DEADLINE_POLICY"); policy != "" {
+		if !slices.Contains(league.DeadlinePolicies, league.DeadlinePolicy(policy)) {
+			panic(fmt.Sprintf("DEADLINE_POLICY environment variable not set to a valid value: invalid policy %q", policy))
+		}
+		config.DeadlinePolicy = league.DeadlinePolicy(policy)
+	}
+
+	return config
+}
+
+func parseInt(name, value string) int {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Sprintf("%s environment variable not set to a valid value: %v", name, err))
+	}
+	return number
+}
+
+// parseDuration parses a duration like "168h" or "30m".
+func parseDuration(name, value string) time.Duration {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		panic(fmt.Sprintf("%s environment variable not set to a valid value: %v", name, err))
+	}
+	return duration
+}
+
+// parseRarities parses a comma separated list of rarities, e.g. "rare,mythic".
+func parseRarities(name, value string) []repository.Rarity {
+	var rarities []repository.Rarity
+	for _, entry := range strings.Split(value, ",") {
+		rarity := repository.Rarity(strings.TrimSpace(entry))
+		if !slices.Contains(repository.Rarities, rarity) {
+			panic(fmt.Sprintf("%s environment variable not set to a valid value: invalid entry %q", name, entry))
+		}
+		rarities = append(rarities, rarity)
+	}
+	return rarities
+}
+
+// parseRarityCounts parses a comma separated list of rarities and counts, e.g. "common=2,rare=1".
+func parseRarityCounts(name, value string) map[repository.Rarity]int {
+	counts := make(map[repository.Rarity]int)
+	for _, entry := range strings.Split(value, ",") {
+		rarity, count, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || !slices.Contains(repository.Rarities, repository.Rarity(rarity)) {
+			panic(fmt.Sprintf("%s environment variable not set to a valid value: invalid entry %q", name, entry))
+		}
+		counts[repository.Rarity(rarity)] = parseInt(name, count)
+	}
+	return counts
+}
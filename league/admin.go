@@ -0,0 +1,165 @@
+package league
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"progression/repository"
+	"progression/scryfall"
+	"strings"
+)
+
+// AdjustBalance adds the given amount of the given currency to the player's balance on behalf of an admin. A negative amount revokes wild cards or packs.
+// The reason is mandatory and recorded in the ledger.
+func (m *Manager) AdjustBalance(ctx context.Context, adminID, userID string, currency repository.Currency, amount int, reason string) (repository.Player, error) {
+	const errMsg = "failed to adjust balance: %w"
+
+	err := m.requireCapability(ctx, adminID, CapabilityAdjust)
+	if err != nil {
+		return repository.Player{}, fmt.Errorf(errMsg, err)
+	}
+
+	if amount == 0 {
+		return repository.Player{}, fmt.Errorf(errMsg, ErrInvalidCount)
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return repository.Player{}, fmt.Errorf(errMsg, ErrMissingReason)
+	}
+
+	round, err := m.dataStore.GetRound()
+	if err != nil {
+		return repository.Player{}, fmt.Errorf(errMsg, err)
+	}
+
+	var player repository.Player
+	err = m.withTx(func(tx *Manager) error {
+		player, err = tx.dataStore.GetPlayerForUpdate(userID)
+		if err != nil {
+			return err
+		}
+
+		if player.Balance(currency)+amount < 0 {
+			return insufficientBalanceError(currency)
+		}
+
+		entry := changeBalance(&player, currency, amount, repository.LedgerReasonAdminAdjustment, round, adminID)
+		entry.Note = reason
+		return tx.updatePlayer(player, []repository.LedgerEntry{entry})
+	})
+	if err != nil {
+		return repository.Player{}, fmt.Errorf(errMsg, err)
+	}
+
+	slog.Info("admin adjusted balance",
+		"admin", adminID, "user", userID, "currency", currency, "amount", amount, "reason", reason)
+
+	return player, nil
+}
+
+// GrantCards adds the given number of copies of a card to the player's card pool on behalf of an admin.
+// The card does not have to be part of an unlocked set. The reason is mandatory and logged.
+func (m *Manager) GrantCards(ctx context.Context, adminID, userID, setCode, collectorNumber string, foil bool, count int, reason string) (repository.Card, error) {
+	const errMsg = "failed to grant cards: %w"
+
+	card, rarity, err := m.prepareCardAdjustment(ctx, adminID, userID, setCode, collectorNumber, foil, count, reason)
+	if err != nil {
+		return repository.Card{}, fmt.Errorf(errMsg, err)
+	}
+
+	round, err := m.dataStore.GetRound()
+	if err != nil {
+		return repository.Card{}, fmt.Errorf(errMsg, err)
+	}
+
+	copies := make([]repository.Card, count)
+	for i := range copies {
+		copies[i] = card
+	}
+
+	err = m.withTx(func(tx *Manager) error {
+		player, err := tx.dataStore.GetPlayerForUpdate(userID)
+		if err != nil {
+			return err
+		}
+
+		rarities := map[string]repository.Rarity{printingKey(card.Set, card.CollectorNumber): rarity}
+		entries, err := tx.storeCards(&player, copies, rarities, repository.LedgerReasonAdminAdjustment, round, adminID)
+		if err != nil {
+			return err
+		}
+
+		return tx.updatePlayer(player, entries)
+	})
+	if err != nil {
+		return repository.Card{}, fmt.Errorf(errMsg, err)
+	}
+
+	slog.Info("admin granted cards",
+		"admin", adminID, "user", userID, "set", card.Set, "collectorNumber", card.CollectorNumber, "foil", foil, "count", count, "reason", reason)
+
+	card.Count = count
+	return card, nil
+}
+
+// RevokeCards removes the given number of copies of a card from the player's card pool on behalf of an admin.
+// The reason is mandatory and logged.
+func (m *Manager) RevokeCards(ctx context.Context, adminID, userID, setCode, collectorNumber string, foil bool, count int, reason string) (repository.Card, error) {
+	const errMsg = "failed to revoke cards: %w"
+
+	card, _, err := m.prepareCardAdjustment(ctx, adminID, userID, setCode, collectorNumber, foil, count, reason)
+	if err != nil {
+		return repository.Card{}, fmt.Errorf(errMsg, err)
+	}
+
+	round, err := m.dataStore.GetRound()
+	if err != nil {
+		return repository.Card{}, fmt.Errorf(errMsg, err)
+	}
+
+	err = m.removeCards(userID, card, count, repository.LedgerReasonAdminAdjustment, round, adminID)
+	if err != nil {
+		return repository.Card{}, fmt.Errorf(errMsg, err)
+	}
+
+	slog.Info("admin revoked cards",
+		"admin", adminID, "user", userID, "set", card.Set, "collectorNumber", card.CollectorNumber, "foil", foil, "count", count, "reason", reason)
+
+	card.Count = count
+	return card, nil
+}
+
+// prepareCardAdjustment validates an admin's request to grant or revoke cards and returns a single copy of the card.
+func (m *Manager) prepareCardAdjustment(ctx context.Context, adminID, userID, setCode, collectorNumber string, foil bool, count int, reason string) (repository.Card, repository.Rarity, error) {
+	err := m.requireCapability(ctx, adminID, CapabilityAdjust)
+	if err != nil {
+		return repository.Card{}, "", err
+	}
+
+	if count < 1 {
+		return repository.Card{}, "", ErrInvalidCount
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		return repository.Card{}, "", ErrMissingReason
+	}
+
+	_, err = m.dataStore.GetPlayer(userID)
+	if err != nil {
+		return repository.Card{}, "", err
+	}
+
+	sfCard, err := m.scryfallClient.GetCard(ctx, setCode, collectorNumber)
+	if err != nil {
+		if errors.Is(err, scryfall.ErrCardNotFound) {
+			return repository.Card{}, "", ErrCardNotFound
+		}
+		return repository.Card{}, "", err
+	}
+
+	card := convertScryfallCard(sfCard)
+	card.Foil = foil
+	return card, convertRarity(sfCard.Rarity), nil
+}
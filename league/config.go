@@ -0,0 +1,73 @@
+package league
+
+import (
+	"progression/repository"
+	"time"
+)
+
+// DeadlinePolicy decides what happens to matches, which haven't been reported by the end of a round.
+type DeadlinePolicy string
+
+const (
+	// DeadlinePolicyDraw reports unreported matches as 0-0-3 draws, so the round ends at the deadline.
+	DeadlinePolicyDraw DeadlinePolicy = "draw"
+	// DeadlinePolicyFlag flags unreported matches for the admins, who resolve them by reporting on behalf of a player.
+	DeadlinePolicyFlag DeadlinePolicy = "flag"
+)
+
+// DeadlinePolicies contains all deadline policies.
+var DeadlinePolicies = []DeadlinePolicy{DeadlinePolicyDraw, DeadlinePolicyFlag}
+
+// Config contains the rules of a league, which can be adjusted to fit the playgroup.
+type Config struct {
+	// RoundRewardWildCards is the number of wild cards per rarity every player is awarded once all matches of a round have been reported.
+	RoundRewardWildCards map[repository.Rarity]int
+	// LossRewardWildPacks is the number of wild packs every player is awarded for losing their match.
+	LossRewardWildPacks int
+	// VaultEnabled enables converting copies of a card beyond the fourth into vault progress, like in MTG Arena.
+	VaultEnabled bool
+	// VaultProgressPerCopy is the vault progress gained for every excess copy of a card of the given rarity.
+	VaultProgressPerCopy map[repository.Rarity]int
+	// VaultSize is the vault progress needed to open the vault.
+	VaultSize int
+	// VaultRewardWildCards is the number of wild cards per rarity awarded whenever the vault is opened.
+	VaultRewardWildCards map[repository.Rarity]int
+	// UntradeableRarities contains the rarities of cards and wild cards, which cannot be traded between players.
+	UntradeableRarities []repository.Rarity
+	// MaxTradesPerRound is the number of trades every player can accept or have accepted per round. Zero means unlimited.
+	MaxTradesPerRound int
+	// RoundDuration is the time players have to report their matches once a round has started. It is stored on the league when it starts.
+	// Zero means rounds have no time limit.
+	RoundDuration time.Duration
+	// ReminderInterval is the time between reminders sent to players, who haven't reported their match yet. Zero disables reminders.
+	ReminderInterval time.Duration
+	// DeadlinePolicy decides what happens to matches, which haven't been reported by the end of a round.
+	DeadlinePolicy DeadlinePolicy
+}
+
+// DefaultConfig returns the rules described in the README: a rare wild card for every player and a wild pack for every losing player.
+// The vault is disabled, but configured similar to MTG Arena's. Rounds have no time limit, but if one is set, players are reminded daily
+// and unreported matches are flagged for the admins.
+func DefaultConfig() Config {
+	return Config{
+		RoundRewardWildCards: map[repository.Rarity]int{
+			repository.RarityRare: 1,
+		},
+		LossRewardWildPacks: 1,
+		VaultEnabled:        false,
+		VaultProgressPerCopy: map[repository.Rarity]int{
+			repository.RarityCommon:   1,
+			repository.RarityUncommon: 3,
+			repository.RarityRare:     10,
+			repository.RarityMythic:   20,
+		},
+		VaultSize: 100,
+		VaultRewardWildCards: map[repository.Rarity]int{
+			repository.RarityUncommon: 3,
+			repository.RarityRare:     2,
+			repository.RarityMythic:   1,
+		},
+		ReminderInterval: 24 * time.Hour,
+		DeadlinePolicy:   DeadlinePolicyFlag,
+	}
+}
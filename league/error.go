@@ -11,8 +11,53 @@ var ErrMatchAlreadyReported = errors.New("match has already been reported")
 // ErrInvalidMatchResult is returned when a player attempts to report a match result, which is not a valid outcome. Currently, this is only 0/0/0.
 var ErrInvalidMatchResult = errors.New("invalid match result")
 
+// ErrNoPreviousRound is returned when an admin attempts to roll back the first round of a league.
+var ErrNoPreviousRound = errors.New("league is in its first round")
+
+// ErrRoundNotOver is returned when an admin attempts to start the next round, while some matches of the current round haven't been reported yet.
+var ErrRoundNotOver = errors.New("not all matches of the round have been reported")
+
 // ErrPlayerAlreadyDropped is returned when a player attempts to drop from a league, which they have already dropped from.
 var ErrPlayerAlreadyDropped = errors.New("player already dropped from the league")
 
 // ErrPlayerNotAdmin is returned when a player attempts to perform an admin-only action.
 var ErrPlayerNotAdmin = errors.New("player is not an admin")
+
+// ErrInsufficientWildCards is returned when a player attempts to redeem a wild card without having any left.
+var ErrInsufficientWildCards = errors.New("not enough wild cards")
+
+// ErrInsufficientWildPacks is returned when a player attempts to redeem more wild packs than they have left.
+var ErrInsufficientWildPacks = errors.New("not enough wild packs")
+
+// ErrInvalidPackCount is returned when a player attempts to redeem less than one pack.
+var ErrInvalidPackCount = errors.New("invalid number of packs")
+
+// ErrInvalidCount is returned when an admin attempts to grant or revoke less than one wild card, pack or card.
+var ErrInvalidCount = errors.New("invalid count")
+
+// ErrMissingReason is returned when an admin attempts to grant or revoke something without giving a reason.
+var ErrMissingReason = errors.New("a reason is required")
+
+// ErrSetNotUnlocked is returned when a given set code does not match any of the unlocked sets.
+var ErrSetNotUnlocked = errors.New("set is not unlocked")
+
+// ErrMaxCopiesOwned is returned when a player attempts to redeem a card, which they already own the maximum number of copies of.
+var ErrMaxCopiesOwned = errors.New("maximum number of copies already owned")
+
+// ErrInvalidTrade is returned when a player attempts to offer an empty trade, a trade with themselves or a trade with invalid items.
+var ErrInvalidTrade = errors.New("invalid trade")
+
+// ErrRarityNotTradeable is returned when a player attempts to trade cards or wild cards of a rarity, which the league's rules prohibit trading.
+var ErrRarityNotTradeable = errors.New("rarity cannot be traded")
+
+// ErrTradeLimitReached is returned when a player attempts to trade after reaching the maximum number of trades in the current round.
+var ErrTradeLimitReached = errors.New("maximum number of trades per round reached")
+
+// ErrTradeNotPending is returned when a player attempts to respond to a trade, which has already been accepted, declined or countered.
+var ErrTradeNotPending = errors.New("trade is no longer pending")
+
+// ErrNotTradeRecipient is returned when a player attempts to respond to a trade, which wasn't offered to them.
+var ErrNotTradeRecipient = errors.New("trade was not offered to player")
+
+// ErrCardNotFound is returned when no card matches the given name or collector number in the unlocked sets.
+var ErrCardNotFound = errors.New("card not found")
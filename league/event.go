@@ -0,0 +1,174 @@
+package league
+
+import (
+	"log/slog"
+	"progression/repository"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Event is something, which happened in the league. Events are only emitted once the change has been stored.
+type Event interface {
+	// Name identifies the kind of event, e.g. "player_joined".
+	Name() string
+}
+
+// PlayerJoined is emitted when a player joins or rejoins the league.
+type PlayerJoined struct {
+	UserID string
+}
+
+// PlayerDropped is emitted when a player drops from the league. An unreported match of the player is forfeited.
+type PlayerDropped struct {
+	UserID string
+}
+
+// MatchReported is emitted when the result of a match has been reported, either by a player or at the round's deadline.
+type MatchReported struct {
+	Pairing repository.Pairing
+	// ReporterID is the user, who reported the match. It is systemActor for matches reported automatically.
+	ReporterID string
+}
+
+// RoundStarted is emitted when a round starts. Deadline is nil, if the round has no time limit.
+type RoundStarted struct {
+	Round     int
+	SetCode   string
+	PlayerIDs []string
+	Deadline  *time.Time
+}
+
+// RoundEnded is emitted when all matches of a round have been reported and the end-of-round rewards have been awarded.
+type RoundEnded struct {
+	Round int
+}
+
+// RoundRolledBack is emitted when a round has been rolled back and the league has returned to the previous round.
+type RoundRolledBack struct {
+	// Round is the round, which has been rolled back.
+	Round int
+	// SetCodes contains the sets, which have been locked again.
+	SetCodes []string
+}
+
+// SetUnlocked is emitted when a set becomes available to redeem wild cards and packs for.
+type SetUnlocked struct {
+	SetCode string
+}
+
+// CardBanned is emitted when a card is banned.
+type CardBanned struct {
+	CardName string
+}
+
+// CardUnbanned is emitted when a card is unbanned.
+type CardUnbanned struct {
+	CardName string
+}
+
+// TradeAccepted is emitted when a trade has been accepted and its items have changed hands.
+type TradeAccepted struct {
+	Trade repository.Trade
+}
+
+// DeadlineReminder is emitted regularly until the round's deadline, as long as some of its matches haven't been reported.
+type DeadlineReminder struct {
+	Round    int
+	Deadline time.Time
+	// Pairings contains the unreported pairings.
+	Pairings []repository.Pairing
+}
+
+// DeadlinePassed is emitted when the round's deadline has passed and its unreported matches have been resolved according to the policy.
+type DeadlinePassed struct {
+	Round  int
+	Policy DeadlinePolicy
+	// Pairings contains the resolved pairings.
+	Pairings []repository.Pairing
+}
+
+func (PlayerJoined) Name() string     { return "player_joined" }
+func (PlayerDropped) Name() string    { return "player_dropped" }
+func (MatchReported) Name() string    { return "match_reported" }
+func (RoundStarted) Name() string     { return "round_started" }
+func (RoundEnded) Name() string       { return "round_ended" }
+func (RoundRolledBack) Name() string  { return "round_rolled_back" }
+func (SetUnlocked) Name() string      { return "set_unlocked" }
+func (CardBanned) Name() string       { return "card_banned" }
+func (CardUnbanned) Name() string     { return "card_unbanned" }
+func (TradeAccepted) Name() string    { return "trade_accepted" }
+func (DeadlineReminder) Name() string { return "deadline_reminder" }
+func (DeadlinePassed) Name() string   { return "deadline_passed" }
+
+// EventHandler is called with every event emitted by the league.
+type EventHandler func(event Event)
+
+// eventQueueSize is the number of events, which are queued for a handler, before publishing waits for it to catch up.
+const eventQueueSize = 64
+
+// EventBus passes the league's events to every subscribed handler.
+// Every handler is called in the background with one event after another in the order they have been published,
+// so slow handlers, e.g. ones sending messages, don't delay the operation, which emitted the event.
+type EventBus struct {
+	mutex  sync.RWMutex
+	queues []chan Event
+	// pending counts the published events, which haven't been handled by every handler yet.
+	pending sync.WaitGroup
+}
+
+// Subscribe calls the handler with every event emitted from now on.
+func (b *EventBus) Subscribe(handler EventHandler) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	queue := make(chan Event, eventQueueSize)
+	b.queues = append(b.queues, queue)
+	go func() {
+		for event := range queue {
+			b.handle(handler, event)
+		}
+	}()
+}
+
+// Wait blocks until every event published so far has been handled by every handler.
+func (b *EventBus) Wait() {
+	b.pending.Wait()
+}
+
+func (b *EventBus) publish(event Event) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for _, queue := range b.queues {
+		b.pending.Add(1)
+		queue <- event
+	}
+}
+
+// handle calls the handler with the event. A panicking handler is logged, so it neither stops the bus nor the program.
+func (b *EventBus) handle(handler EventHandler, event Event) {
+	defer b.pending.Done()
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Event handler panicked", "event", event.Name(), "panic", r, "stack", string(debug.Stack()))
+		}
+	}()
+
+	handler(event)
+}
+
+// Events returns the bus the league's events are published on.
+func (m *Manager) Events() *EventBus {
+	return m.events
+}
+
+// emit publishes the event. Inside a transaction, the event is held back until the transaction has been committed and dropped on rollback.
+func (m *Manager) emit(event Event) {
+	if m.pendingEvents != nil {
+		*m.pendingEvents = append(*m.pendingEvents, event)
+		return
+	}
+
+	m.events.publish(event)
+}
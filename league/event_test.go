@@ -0,0 +1,65 @@
+package league
+
+import (
+	"errors"
+	"progression/repository/repositorytest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTx_events(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected []Event
+	}{
+		{name: "commit", expected: []Event{PlayerJoined{UserID: "a"}, CardBanned{CardName: "Lurrus of the Dream-Den"}}},
+		{name: "rollback", err: errors.New("rollback")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewLeagueManager(repositorytest.NewMemoryDataStore(), nil, nil, DefaultConfig())
+			var published []Event
+			manager.Events().Subscribe(func(event Event) {
+				published = append(published, event)
+			})
+
+			err := manager.withTx(func(tx *Manager) error {
+				tx.emit(PlayerJoined{UserID: "a"})
+				return tx.withTx(func(tx *Manager) error {
+					tx.emit(CardBanned{CardName: "Lurrus of the Dream-Den"})
+					manager.Events().Wait()
+					assert.Empty(t, published, "events must not be published before the transaction has been committed")
+					return tt.err
+				})
+			})
+
+			assert.ErrorIs(t, err, tt.err)
+			manager.Events().Wait()
+			assert.Equal(t, tt.expected, published)
+		})
+	}
+}
+
+func TestEventBus(t *testing.T) {
+	bus := &EventBus{}
+	release := make(chan struct{})
+	var published []Event
+	bus.Subscribe(func(event Event) {
+		<-release
+		published = append(published, event)
+	})
+	bus.Subscribe(func(event Event) {
+		panic("handler failed")
+	})
+
+	// publishing doesn't wait for the handlers
+	bus.publish(PlayerJoined{UserID: "a"})
+	bus.publish(PlayerDropped{UserID: "a"})
+	close(release)
+	bus.Wait()
+
+	assert.Equal(t, []Event{PlayerJoined{UserID: "a"}, PlayerDropped{UserID: "a"}}, published, "expected the events in the order they have been published")
+}
@@ -0,0 +1,34 @@
+package league
+
+import (
+	"bytes"
+	"encoding/csv"
+	"progression/repository"
+	"strconv"
+)
+
+// ExportCardList returns the given cards as CSV, including their foil status and links.
+func ExportCardList(cards []repository.Card) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	rows := make([][]string, 0, len(cards)+1)
+	rows = append(rows, []string{"count", "name", "set", "collector_number", "foil", "scryfall_uri", "image_url"})
+	for _, card := range cards {
+		rows = append(rows, []string{
+			strconv.Itoa(card.Count),
+			card.Name,
+			card.Set,
+			card.CollectorNumber,
+			strconv.FormatBool(card.Foil),
+			card.ScryfallURI,
+			card.ImageURL,
+		})
+	}
+
+	err := writer.WriteAll(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
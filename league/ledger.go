@@ -0,0 +1,85 @@
+package league
+
+import (
+	"fmt"
+	"progression/repository"
+)
+
+// systemActor is recorded as the actor of balance changes, which are not caused by any user directly.
+const systemActor = "system"
+
+// changeBalance changes the player's balance and returns the ledger entry recording the change.
+func changeBalance(player *repository.Player, currency repository.Currency, amount int, reason repository.LedgerReason, round int, actor string) repository.LedgerEntry {
+	player.AddBalance(currency, amount)
+	return repository.LedgerEntry{
+		PlayerId: player.Id,
+		Currency: currency,
+		Amount:   amount,
+		Reason:   reason,
+		Round:    round,
+		Actor:    actor,
+	}
+}
+
+// updatePlayer stores the player and appends the given ledger entries, which explain the changes to their balance.
+// Both are stored together or not at all.
+func (m *Manager) updatePlayer(player repository.Player, entries []repository.LedgerEntry) error {
+	return m.withTx(func(tx *Manager) error {
+		err := tx.dataStore.UpdatePlayer(player)
+		if err != nil {
+			return err
+		}
+
+		return tx.dataStore.AppendLedgerEntries(entries)
+	})
+}
+
+// GetLedger returns every change to the player's balance, oldest first.
+func (m *Manager) GetLedger(userID string) ([]repository.LedgerEntry, error) {
+	const errMsg = "failed to get ledger: %w"
+
+	entries, err := m.dataStore.GetLedgerEntries(userID)
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	return entries, nil
+}
+
+// ReconcileBalance compares the player's balance with the sum of their ledger entries.
+// It returns the difference between both for every currency, where they don't match.
+func (m *Manager) ReconcileBalance(userID string) (map[repository.Currency]int, error) {
+	const errMsg = "failed to reconcile balance: %w"
+
+	player, err := m.dataStore.GetPlayer(userID)
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	entries, err := m.dataStore.GetLedgerEntries(userID)
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	return reconcileBalance(player, entries), nil
+}
+
+func reconcileBalance(player repository.Player, entries []repository.LedgerEntry) map[repository.Currency]int {
+	sums := make(map[repository.Currency]int)
+	for _, entry := range entries {
+		sums[entry.Currency] += entry.Amount
+	}
+
+	currencies := []repository.Currency{repository.CurrencyWildPack}
+	for _, rarity := range repository.Rarities {
+		currencies = append(currencies, repository.WildCardCurrency(rarity))
+	}
+
+	differences := make(map[repository.Currency]int)
+	for _, currency := range currencies {
+		if difference := player.Balance(currency) - sums[currency]; difference != 0 {
+			differences[currency] = difference
+		}
+	}
+	return differences
+}
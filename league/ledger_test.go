@@ -0,0 +1,34 @@
+package league
+
+import (
+	"progression/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcileBalance_matching(t *testing.T) {
+	player := repository.Player{Id: "test_player"}
+	entries := []repository.LedgerEntry{
+		changeBalance(&player, repository.WildCardCurrency(repository.RarityRare), 1, repository.LedgerReasonRoundReward, 0, systemActor),
+		changeBalance(&player, repository.CurrencyWildPack, 2, repository.LedgerReasonLossReward, 0, systemActor),
+		changeBalance(&player, repository.CurrencyWildPack, -1, repository.LedgerReasonRedemption, 1, player.Id),
+	}
+
+	assert.Equal(t, 1, player.WildRares, "wild rares did not match")
+	assert.Equal(t, 1, player.WildPacks, "wild packs did not match")
+	assert.Empty(t, reconcileBalance(player, entries), "expected balance to match ledger")
+}
+
+func TestReconcileBalance_differences(t *testing.T) {
+	player := repository.Player{Id: "test_player", WildMythics: 2, WildPacks: 1}
+	entries := []repository.LedgerEntry{
+		{PlayerId: player.Id, Currency: repository.CurrencyWildPack, Amount: 3, Reason: repository.LedgerReasonLossReward},
+	}
+
+	differences := reconcileBalance(player, entries)
+	assert.Equal(t, map[repository.Currency]int{
+		repository.WildCardCurrency(repository.RarityMythic): 2,
+		repository.CurrencyWildPack:                          -2,
+	}, differences, "differences did not match")
+}
@@ -1,21 +1,47 @@
 package league
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"progression/packGenerator"
 	"progression/repository"
-	"strconv"
+	"progression/scryfall"
+	"strings"
+	"time"
+
+	sf "github.com/BlueMonday/go-scryfall"
 )
 
+// firstRound is the number of the round every league starts with.
+const firstRound = 0
+
+// startingPacks is the number of packs every player opens when a league starts.
+const startingPacks = 10
+
+// roundWildPacks is the number of wild packs every player is given when a new round starts.
+const roundWildPacks = 10
+
+// ProgressFunc is called with the number of finished and total steps of a slow operation.
+type ProgressFunc func(done, total int)
+
 type Manager struct {
-	dataStore  repository.DataStore
-	mbpgClient packGenerator.Client
+	dataStore      repository.DataStore
+	mbpgClient     *packGenerator.Client
+	scryfallClient *scryfall.Client
+	config         Config
+	events         *EventBus
+	// pendingEvents collects the events emitted inside a transaction. It is nil outside of transactions.
+	pendingEvents *[]Event
 }
 
-func NewLeagueManager(dataStore repository.DataStore) *Manager {
+func NewLeagueManager(dataStore repository.DataStore, mbpgClient *packGenerator.Client, scryfallClient *scryfall.Client, config Config) *Manager {
 	return &Manager{
-		dataStore: dataStore,
+		dataStore:      dataStore,
+		mbpgClient:     mbpgClient,
+		scryfallClient: scryfallClient,
+		config:         config,
+		events:         &EventBus{},
 	}
 }
 
@@ -30,6 +56,7 @@ func (m *Manager) JoinLeague(userID string) error {
 			if err != nil {
 				return fmt.Errorf(errMsg, err)
 			}
+			m.emit(PlayerJoined{UserID: userID})
 			return nil
 		}
 		return fmt.Errorf(errMsg, ErrPlayerAlreadyJoined)
@@ -40,14 +67,13 @@ func (m *Manager) JoinLeague(userID string) error {
 	}
 
 	err = m.dataStore.UpdatePlayer(repository.Player{
-		Id:        userID,
-		WildCards: 0,
-		WildPacks: 0,
+		Id: userID,
 	})
 	if err != nil {
 		return fmt.Errorf(errMsg, err)
 	}
 
+	m.emit(PlayerJoined{UserID: userID})
 	return nil
 }
 
@@ -73,16 +99,28 @@ func (m *Manager) GetBannedCards() ([]repository.Ban, error) {
 	return bans, nil
 }
 
-func (m *Manager) BanCard(userID, cardName string) error {
-	const errMsg = "failed to ban card: %w"
+// SuggestCardNames returns card names, which could be completions of the given partial name.
+func (m *Manager) SuggestCardNames(ctx context.Context, partialName string) ([]string, error) {
+	const errMsg = "failed to suggest card names: %w"
+
+	if partialName == "" {
+		return nil, nil
+	}
 
-	isAdmin, err := m.dataStore.IsAdmin(userID)
+	cardNames, err := m.scryfallClient.AutocompleteCardName(ctx, partialName)
 	if err != nil {
-		return fmt.Errorf(errMsg, err)
+		return nil, fmt.Errorf(errMsg, err)
 	}
 
-	if !isAdmin {
-		return ErrPlayerNotAdmin
+	return cardNames, nil
+}
+
+func (m *Manager) BanCard(ctx context.Context, userID, cardName string) error {
+	const errMsg = "failed to ban card: %w"
+
+	err := m.requireCapability(ctx, userID, CapabilityBan)
+	if err != nil {
+		return fmt.Errorf(errMsg, err)
 	}
 
 	err = m.dataStore.BanCard(cardName)
@@ -90,36 +128,43 @@ func (m *Manager) BanCard(userID, cardName string) error {
 		return fmt.Errorf(errMsg, err)
 	}
 
+	m.emit(CardBanned{CardName: cardName})
 	return nil
 }
 
-func (m *Manager) UnbanCard(userID, cardName string) error {
+func (m *Manager) UnbanCard(ctx context.Context, userID, cardName string) error {
 	const errMsg = "failed to unban card: %w"
 
-	isAdmin, err := m.dataStore.IsAdmin(userID)
+	err := m.requireCapability(ctx, userID, CapabilityBan)
 	if err != nil {
 		return fmt.Errorf(errMsg, err)
 	}
 
-	if !isAdmin {
-		return ErrPlayerNotAdmin
-	}
-
 	err = m.dataStore.UnbanCard(cardName)
 	if err != nil {
 		return fmt.Errorf(errMsg, err)
 	}
 
+	m.emit(CardUnbanned{CardName: cardName})
 	return nil
 }
 
-func (m *Manager) ReportMatch(userID string, wins, losses, draws int) error {
+// ReportMatch reports the result of the user's match in the current round from the user's point of view.
+// Reporting on behalf of another user, e.g. to resolve a flagged match, requires CapabilityReport.
+func (m *Manager) ReportMatch(ctx context.Context, reporterID, userID string, wins, losses, draws int) error {
 	const errMsg = "failed to report match: %w"
 
 	if wins == 0 && losses == 0 && draws == 0 {
 		return fmt.Errorf(errMsg, ErrInvalidMatchResult)
 	}
 
+	if reporterID != userID {
+		err := m.requireCapability(ctx, reporterID, CapabilityReport)
+		if err != nil {
+			return fmt.Errorf(errMsg, err)
+		}
+	}
+
 	pairing, err := m.dataStore.GetPairing(userID)
 	if err != nil {
 		return fmt.Errorf(errMsg, err)
@@ -139,7 +184,15 @@ func (m *Manager) ReportMatch(userID string, wins, losses, draws int) error {
 		pairing.Draws = draws
 	}
 
-	err = m.dataStore.UpdatePairing(pairing)
+	err = m.withTx(func(tx *Manager) error {
+		err := tx.dataStore.UpdatePairing(pairing)
+		if err != nil {
+			return err
+		}
+
+		tx.emit(MatchReported{Pairing: pairing, ReporterID: reporterID})
+		return tx.awardRewardsIfRoundOver(pairing.Round)
+	})
 	if err != nil {
 		return fmt.Errorf(errMsg, err)
 	}
@@ -151,56 +204,326 @@ func isMatchReported(pairing repository.Pairing) bool {
 	return pairing.Wins1 != 0 || pairing.Wins2 != 0 || pairing.Draws != 0
 }
 
-func (m *Manager) StartRound(set string) (map[string][]repository.Card, error) {
-	const errMsg = "failed to start round: %w"
+// awardRewardsIfRoundOver awards the end-of-round rewards to every player, once all matches of the given round have been reported.
+// It has to be called after every reported match, since the last report ends the round. RoundEnded is emitted, once the round is over.
+// It has to be called inside a transaction, so the players stay locked until their rewards are stored.
+func (m *Manager) awardRewardsIfRoundOver(round int) error {
+	pairings, err := m.dataStore.GetPairings(round)
+	if err != nil {
+		return err
+	}
+
+	losers := make(map[string]bool)
+	for _, pairing := range pairings {
+		if !isMatchReported(pairing) {
+			return nil
+		}
+
+		switch {
+		case pairing.Wins1 < pairing.Wins2:
+			losers[pairing.Player1] = true
+		case pairing.Wins2 < pairing.Wins1:
+			losers[pairing.Player2] = true
+		}
+	}
 
 	players, err := m.dataStore.GetAllPlayers()
 	if err != nil {
-		return nil, fmt.Errorf(errMsg, err)
+		return err
 	}
 
-	playerPools := make(map[string][]repository.Card)
 	for _, player := range players {
-		cards, err := m.mbpgClient.GetPacks(set, 10)
+		player, err = m.dataStore.GetPlayerForUpdate(player.Id)
 		if err != nil {
-			return nil, fmt.Errorf(errMsg, err)
+			return err
+		}
+
+		var entries []repository.LedgerEntry
+		for rarity, count := range m.config.RoundRewardWildCards {
+			if count != 0 {
+				entries = append(entries, changeBalance(&player, repository.WildCardCurrency(rarity), count,
+					repository.LedgerReasonRoundReward, round, systemActor))
+			}
+		}
+
+		if losers[player.Id] && m.config.LossRewardWildPacks != 0 {
+			entries = append(entries, changeBalance(&player, repository.CurrencyWildPack, m.config.LossRewardWildPacks,
+				repository.LedgerReasonLossReward, round, systemActor))
+		}
+
+		err = m.updatePlayer(player, entries)
+		if err != nil {
+			return err
 		}
+	}
+
+	m.emit(RoundEnded{Round: round})
+	return nil
+}
+
+// StartRound starts a new league with all players, who have joined so far. The given set is unlocked and every player opens startingPacks packs of it.
+// All packs are generated first, so the card pools, the pairings and the league state are stored together or not at all.
+// It returns the opened packs by player. If given, progress is called whenever a player's packs have been opened.
+func (m *Manager) StartRound(ctx context.Context, userID, set string, progress ProgressFunc) (map[string][]Pack, error) {
+	const errMsg = "failed to start round: %w"
+
+	err := m.requireCapability(ctx, userID, CapabilityStart)
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
 
-		convertedCards := convertCardsFormat(cards)
-		err = m.dataStore.StoreCards(player.Id, convertedCards)
+	players, err := m.dataStore.GetAllPlayers()
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	playerPacks := make(map[string][]Pack, len(players))
+	for idx, player := range players {
+		packs, err := m.openPacks(ctx, set, startingPacks)
 		if err != nil {
 			return nil, fmt.Errorf(errMsg, err)
 		}
 
-		playerPools[player.Id] = convertedCards
+		playerPacks[player.Id] = packs
+		if progress != nil {
+			progress(idx+1, len(players))
+		}
 	}
 
-	err = m.dataStore.StartLeague()
+	err = m.withTx(func(tx *Manager) error {
+		err := tx.dataStore.StartLeague()
+		if err != nil {
+			return err
+		}
+
+		deadline, err := tx.scheduleLeague(time.Now())
+		if err != nil {
+			return err
+		}
+
+		err = tx.dataStore.UnlockSet(strings.ToUpper(set), firstRound)
+		if err != nil {
+			return err
+		}
+
+		playerIDs := make([]string, 0, len(players))
+		for _, player := range players {
+			playerIDs = append(playerIDs, player.Id)
+		}
+		tx.emit(SetUnlocked{SetCode: strings.ToUpper(set)})
+		tx.emit(RoundStarted{Round: firstRound, SetCode: strings.ToUpper(set), PlayerIDs: playerIDs, Deadline: deadline})
+
+		for _, player := range players {
+			player, err := tx.dataStore.GetPlayerForUpdate(player.Id)
+			if err != nil {
+				return err
+			}
+
+			entries, err := tx.storeCards(&player, flattenPacks(playerPacks[player.Id]), packRarities(playerPacks[player.Id]), repository.LedgerReasonStartingPacks, firstRound, systemActor)
+			if err != nil {
+				return err
+			}
+
+			err = tx.updatePlayer(player, entries)
+			if err != nil {
+				return err
+			}
+		}
+
+		pairings := generatePairings(players, firstRound)
+		if len(pairings) == 0 {
+			return nil
+		}
+
+		return tx.dataStore.StorePairings(pairings)
+	})
 	if err != nil {
 		return nil, fmt.Errorf(errMsg, err)
 	}
 
-	return playerPools, nil
+	return playerPacks, nil
+}
+
+// NextRound starts the next round of the active league, once all matches of the current round have been reported.
+// The given set is unlocked, every player is given roundWildPacks wild packs and new pairings are generated. It returns the new round.
+func (m *Manager) NextRound(ctx context.Context, userID, set string) (int, error) {
+	const errMsg = "failed to start next round: %w"
+
+	err := m.requireCapability(ctx, userID, CapabilityStart)
+	if err != nil {
+		return 0, fmt.Errorf(errMsg, err)
+	}
+
+	round, err := m.dataStore.GetRound()
+	if err != nil {
+		return 0, fmt.Errorf(errMsg, err)
+	}
+
+	pairings, err := m.dataStore.GetPairings(round)
+	if err != nil {
+		return 0, fmt.Errorf(errMsg, err)
+	}
+
+	for _, pairing := range pairings {
+		if !isMatchReported(pairing) {
+			return 0, fmt.Errorf(errMsg, ErrRoundNotOver)
+		}
+	}
+
+	players, err := m.dataStore.GetAllPlayers()
+	if err != nil {
+		return 0, fmt.Errorf(errMsg, err)
+	}
+
+	setCode := strings.ToUpper(set)
+	err = m.withTx(func(tx *Manager) error {
+		var err error
+		round, err = tx.dataStore.NextRound()
+		if err != nil {
+			return err
+		}
+
+		deadline, err := tx.scheduleRound(time.Now())
+		if err != nil {
+			return err
+		}
+
+		err = tx.dataStore.UnlockSet(setCode, round)
+		if err != nil {
+			return err
+		}
+
+		playerIDs := make([]string, 0, len(players))
+		for _, player := range players {
+			playerIDs = append(playerIDs, player.Id)
+			player, err := tx.dataStore.GetPlayerForUpdate(player.Id)
+			if err != nil {
+				return err
+			}
+
+			entry := changeBalance(&player, repository.CurrencyWildPack, roundWildPacks, repository.LedgerReasonRoundPacks, round, systemActor)
+			err = tx.updatePlayer(player, []repository.LedgerEntry{entry})
+			if err != nil {
+				return err
+			}
+		}
+		tx.emit(SetUnlocked{SetCode: setCode})
+		tx.emit(RoundStarted{Round: round, SetCode: setCode, PlayerIDs: playerIDs, Deadline: deadline})
+
+		pairings := generatePairings(players, round)
+		if len(pairings) == 0 {
+			return nil
+		}
+
+		return tx.dataStore.StorePairings(pairings)
+	})
+	if err != nil {
+		return 0, fmt.Errorf(errMsg, err)
+	}
+
+	return round, nil
+}
+
+// EndLeague ends the active league. Players, card pools and pairings are kept.
+func (m *Manager) EndLeague(ctx context.Context, userID string) error {
+	const errMsg = "failed to end league: %w"
+
+	err := m.requireCapability(ctx, userID, CapabilityStart)
+	if err != nil {
+		return fmt.Errorf(errMsg, err)
+	}
+
+	err = m.dataStore.EndLeague()
+	if err != nil {
+		return fmt.Errorf(errMsg, err)
+	}
+
+	return nil
+}
+
+// withTx runs the given function in a transaction of the datastore.
+// The manager passed to the function uses the transaction, so all of its operations are committed or rolled back together.
+// Events emitted by the function are only published once the transaction has been committed.
+func (m *Manager) withTx(f func(tx *Manager) error) error {
+	var events []Event
+	err := m.dataStore.WithTx(func(tx repository.DataStore) error {
+		txManager := *m
+		txManager.dataStore = tx
+		txManager.pendingEvents = &events
+		return f(&txManager)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		m.emit(event)
+	}
+	return nil
 }
 
 func convertCardsFormat(cards []packGenerator.Card) []repository.Card {
 	convertedCards := make([]repository.Card, 0, len(cards))
 	for _, card := range cards {
-		collectorNumber, err := strconv.Atoi(card.CollectorNumber)
-		if err != nil {
-			// TODO there is no recovery planned from this problem
-			panic(err)
-		}
 		convertedCards = append(convertedCards, repository.Card{
 			Name:            card.Name,
 			Set:             card.Set,
-			CollectorNumber: collectorNumber,
+			CollectorNumber: card.CollectorNumber,
+			Foil:            card.Foil,
+			ScryfallURI:     card.ScryfallURI,
+			ImageURL:        card.ImageURL,
 			Count:           1,
 		})
 	}
 	return convertedCards
 }
 
+// convertRarity maps scryfall's rarities to the rarities of wild cards. Special and bonus cards are treated as mythics.
+func convertRarity(rarity string) repository.Rarity {
+	switch rarity {
+	case "common":
+		return repository.RarityCommon
+	case "uncommon":
+		return repository.RarityUncommon
+	case "rare":
+		return repository.RarityRare
+	default:
+		return repository.RarityMythic
+	}
+}
+
+// convertScryfallCard converts a single non-foil printing found on scryfall.
+func convertScryfallCard(sfCard sf.Card) repository.Card {
+	card := repository.Card{
+		Name:            sfCard.Name,
+		Set:             strings.ToUpper(sfCard.Set),
+		CollectorNumber: sfCard.CollectorNumber,
+		ScryfallURI:     sfCard.ScryfallURI,
+		Count:           1,
+	}
+
+	switch {
+	case sfCard.ImageURIs != nil:
+		card.ImageURL = sfCard.ImageURIs.Normal
+	case len(sfCard.CardFaces) > 0:
+		card.ImageURL = sfCard.CardFaces[0].ImageURIs.Normal
+	}
+
+	return card
+}
+
+// GetPlayers returns all players, who haven't dropped from the league.
+func (m *Manager) GetPlayers() ([]repository.Player, error) {
+	const errMsg = "failed to get players: %w"
+
+	players, err := m.dataStore.GetAllPlayers()
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	return players, nil
+}
+
 func (m *Manager) GetPlayerCards(userID string) ([]repository.Card, error) {
 	const errMsg = "failed to get player cards: %w"
 
@@ -240,7 +563,8 @@ func (m *Manager) DropPlayer(userID string) error {
 		return fmt.Errorf(errMsg, err)
 	}
 
-	if err == nil && !isMatchReported(pairing) {
+	forfeited := err == nil && !isMatchReported(pairing)
+	if forfeited {
 		if pairing.Player1 == userID {
 			pairing.Wins1 = 0
 			pairing.Wins2 = 2
@@ -249,17 +573,231 @@ func (m *Manager) DropPlayer(userID string) error {
 			pairing.Wins2 = 0
 		}
 		pairing.Draws = 0
+	}
+
+	err = m.withTx(func(tx *Manager) error {
+		if forfeited {
+			err := tx.dataStore.UpdatePairing(pairing)
+			if err != nil {
+				return err
+			}
+		}
 
-		err = m.dataStore.UpdatePairing(pairing)
+		err := tx.dataStore.DropPlayer(userID)
 		if err != nil {
-			return fmt.Errorf(errMsg, err)
+			return err
 		}
-	}
 
-	err = m.dataStore.DropPlayer(userID)
+		tx.emit(PlayerDropped{UserID: userID})
+		if forfeited {
+			tx.emit(MatchReported{Pairing: pairing, ReporterID: userID})
+			return tx.awardRewardsIfRoundOver(pairing.Round)
+		}
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf(errMsg, err)
 	}
 
 	return nil
 }
+
+// FindCardPrintings returns every printing of the card with the given name in any of the unlocked sets.
+func (m *Manager) FindCardPrintings(ctx context.Context, cardName string) ([]repository.Card, error) {
+	const errMsg = "failed to find card printings: %w"
+
+	sets, err := m.dataStore.GetSets()
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	if len(sets) == 0 {
+		return nil, fmt.Errorf(errMsg, ErrCardNotFound)
+	}
+
+	setCodes := make([]string, len(sets))
+	for i, set := range sets {
+		setCodes[i] = set.SetCode
+	}
+
+	sfCards, err := m.scryfallClient.SearchCardInSets(ctx, scryfall.ExactName(cardName), setCodes, scryfall.WithAllPrintings)
+	if err != nil {
+		if errors.Is(err, scryfall.ErrCardNotFound) {
+			return nil, fmt.Errorf(errMsg, ErrCardNotFound)
+		}
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	printings := make([]repository.Card, 0, len(sfCards))
+	for _, sfCard := range sfCards {
+		printings = append(printings, convertScryfallCard(sfCard))
+	}
+
+	return printings, nil
+}
+
+// RedeemCard spends one of the player's wild cards to add the card with the given collector number from the given set to their card pool.
+func (m *Manager) RedeemCard(ctx context.Context, userID, setCode, collectorNumber string) (repository.Card, error) {
+	const errMsg = "failed to redeem card: %w"
+
+	round, err := m.dataStore.GetRound()
+	if err != nil {
+		return repository.Card{}, fmt.Errorf(errMsg, err)
+	}
+
+	player, err := m.getActivePlayer(userID)
+	if err != nil {
+		return repository.Card{}, fmt.Errorf(errMsg, err)
+	}
+
+	setCode, err = m.getUnlockedSetCode(setCode)
+	if err != nil {
+		return repository.Card{}, fmt.Errorf(errMsg, err)
+	}
+
+	sfCard, err := m.scryfallClient.GetCard(ctx, setCode, collectorNumber)
+	if err != nil {
+		if errors.Is(err, scryfall.ErrCardNotFound) {
+			return repository.Card{}, fmt.Errorf(errMsg, ErrCardNotFound)
+		}
+		return repository.Card{}, fmt.Errorf(errMsg, err)
+	}
+
+	card := convertScryfallCard(sfCard)
+	card.Set = setCode
+
+	rarity := convertRarity(sfCard.Rarity)
+	if player.WildCards(rarity) < 1 {
+		return repository.Card{}, fmt.Errorf(errMsg, fmt.Errorf("%w of rarity %s", ErrInsufficientWildCards, rarity))
+	}
+
+	// the balance is checked again in the transaction, since it might have changed while the card was looked up
+	err = m.withTx(func(tx *Manager) error {
+		player, err := tx.dataStore.GetPlayerForUpdate(userID)
+		if err != nil {
+			return err
+		}
+
+		if player.WildCards(rarity) < 1 {
+			return fmt.Errorf("%w of rarity %s", ErrInsufficientWildCards, rarity)
+		}
+
+		if tx.config.VaultEnabled {
+			maxCopiesOwned, err := tx.ownsMaxCopies(userID, card.Name)
+			if err != nil {
+				return err
+			}
+
+			if maxCopiesOwned {
+				return ErrMaxCopiesOwned
+			}
+		}
+
+		entry := changeBalance(&player, repository.WildCardCurrency(rarity), -1, repository.LedgerReasonRedemption, round, userID)
+		err = tx.updatePlayer(player, []repository.LedgerEntry{entry})
+		if err != nil {
+			return err
+		}
+
+		return tx.addCards(userID, []repository.Card{card}, repository.LedgerReasonRedemption, round, userID)
+	})
+	if err != nil {
+		return repository.Card{}, fmt.Errorf(errMsg, err)
+	}
+
+	return card, nil
+}
+
+// RedeemPacks spends the given number of the player's wild packs to add that many packs of the given set to their card pool.
+// It returns the opened packs.
+func (m *Manager) RedeemPacks(ctx context.Context, userID, setCode string, count int) ([]Pack, error) {
+	const errMsg = "failed to redeem packs: %w"
+
+	if count < 1 {
+		return nil, fmt.Errorf(errMsg, ErrInvalidPackCount)
+	}
+
+	round, err := m.dataStore.GetRound()
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	player, err := m.getActivePlayer(userID)
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	if player.WildPacks < count {
+		return nil, fmt.Errorf(errMsg, ErrInsufficientWildPacks)
+	}
+
+	setCode, err = m.getUnlockedSetCode(setCode)
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	packs, err := m.openPacks(ctx, setCode, count)
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	// the balance is checked again in the transaction, since it might have changed while the packs were opened
+	err = m.withTx(func(tx *Manager) error {
+		player, err := tx.dataStore.GetPlayerForUpdate(userID)
+		if err != nil {
+			return err
+		}
+
+		if player.WildPacks < count {
+			return ErrInsufficientWildPacks
+		}
+
+		entries, err := tx.storeCards(&player, flattenPacks(packs), packRarities(packs), repository.LedgerReasonRedemption, round, userID)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, changeBalance(&player, repository.CurrencyWildPack, -count, repository.LedgerReasonRedemption, round, userID))
+		return tx.updatePlayer(player, entries)
+	})
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	return packs, nil
+}
+
+// Config returns the rules of the league.
+func (m *Manager) Config() Config {
+	return m.config
+}
+
+// getActivePlayer returns the player with the given userID, unless they have dropped from the league.
+func (m *Manager) getActivePlayer(userID string) (repository.Player, error) {
+	player, err := m.dataStore.GetPlayer(userID)
+	if err != nil {
+		return repository.Player{}, err
+	}
+
+	if player.Dropped {
+		return repository.Player{}, ErrPlayerAlreadyDropped
+	}
+
+	return player, nil
+}
+
+// getUnlockedSetCode returns the set code of the unlocked set matching the given set code, ignoring case.
+func (m *Manager) getUnlockedSetCode(setCode string) (string, error) {
+	sets, err := m.dataStore.GetSets()
+	if err != nil {
+		return "", err
+	}
+
+	for _, set := range sets {
+		if strings.EqualFold(set.SetCode, setCode) {
+			return set.SetCode, nil
+		}
+	}
+
+	return "", ErrSetNotUnlocked
+}
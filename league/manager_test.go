@@ -0,0 +1,178 @@
+package league
+
+import (
+	"context"
+	"progression/repository"
+	"progression/repository/repositorytest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRoundDataStore returns a datastore with a league in the round of the given pairing, the players "a" and "b" and the unlocked set IKO.
+func newRoundDataStore(t *testing.T, pairing repository.Pairing) *repositorytest.MemoryDataStore {
+	t.Helper()
+
+	dataStore := repositorytest.NewMemoryDataStore()
+	require.NoError(t, dataStore.StartLeague())
+	for range pairing.Round {
+		_, err := dataStore.NextRound()
+		require.NoError(t, err)
+	}
+	require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: "a", WildPacks: 1}))
+	require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: "b"}))
+	require.NoError(t, dataStore.StorePairings([]repository.Pairing{pairing}))
+	require.NoError(t, dataStore.UnlockSet("IKO", 0))
+	return dataStore
+}
+
+// getSetCodes returns the codes of the unlocked sets.
+func getSetCodes(t *testing.T, dataStore repository.DataStore) []string {
+	t.Helper()
+
+	sets, err := dataStore.GetSets()
+	require.NoError(t, err)
+
+	setCodes := make([]string, 0, len(sets))
+	for _, set := range sets {
+		setCodes = append(setCodes, set.SetCode)
+	}
+	return setCodes
+}
+
+// getPlayer returns the stored player.
+func getPlayer(t *testing.T, dataStore repository.DataStore, userID string) repository.Player {
+	t.Helper()
+
+	player, err := dataStore.GetPlayer(userID)
+	require.NoError(t, err)
+	return player
+}
+
+// getRound returns the current round of the active league.
+func getRound(t *testing.T, dataStore repository.DataStore) int {
+	t.Helper()
+
+	round, err := dataStore.GetRound()
+	require.NoError(t, err)
+	return round
+}
+
+// getLedgerEntries returns the ledger entries of every given player, in the order of the players.
+func getLedgerEntries(t *testing.T, dataStore repository.DataStore, userIDs ...string) []repository.LedgerEntry {
+	t.Helper()
+
+	var entries []repository.LedgerEntry
+	for _, userID := range userIDs {
+		playerEntries, err := dataStore.GetLedgerEntries(userID)
+		require.NoError(t, err)
+		entries = append(entries, playerEntries...)
+	}
+	return entries
+}
+
+func TestNextRound(t *testing.T) {
+	dataStore := newRoundDataStore(t, repository.Pairing{Round: 0, Player1: "a", Player2: "b", Wins1: 2, Wins2: 1})
+	manager := NewLeagueManager(dataStore, nil, nil, DefaultConfig())
+	var published []Event
+	manager.Events().Subscribe(func(event Event) {
+		published = append(published, event)
+	})
+
+	ctx := WithCapabilities(context.Background(), []Capability{CapabilityStart})
+	round, err := manager.NextRound(ctx, "admin", "m21")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, round)
+	assert.Equal(t, []string{"IKO", "M21"}, getSetCodes(t, dataStore))
+	assert.Equal(t, 1+roundWildPacks, getPlayer(t, dataStore, "a").WildPacks)
+	assert.Equal(t, roundWildPacks, getPlayer(t, dataStore, "b").WildPacks)
+	entries := getLedgerEntries(t, dataStore, "a", "b")
+	assert.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, repository.LedgerReasonRoundPacks, entry.Reason)
+		assert.Equal(t, 1, entry.Round)
+	}
+
+	pairings, _ := dataStore.GetPairings(1)
+	assert.Len(t, pairings, 1)
+	manager.Events().Wait()
+	require.Len(t, published, 2)
+	assert.Equal(t, SetUnlocked{SetCode: "M21"}, published[0])
+	assert.IsType(t, RoundStarted{}, published[1])
+}
+
+func TestNextRound_errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities []Capability
+		pairing      repository.Pairing
+		expected     error
+	}{
+		{name: "not_admin", pairing: repository.Pairing{Player1: "a", Player2: "b", Wins1: 2}, expected: ErrPlayerNotAdmin},
+		{name: "round_not_over", capabilities: []Capability{CapabilityStart}, pairing: repository.Pairing{Player1: "a", Player2: "b"}, expected: ErrRoundNotOver},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataStore := newRoundDataStore(t, tt.pairing)
+			manager := NewLeagueManager(dataStore, nil, nil, DefaultConfig())
+
+			_, err := manager.NextRound(WithCapabilities(context.Background(), tt.capabilities), "admin", "M21")
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Equal(t, 0, getRound(t, dataStore))
+			assert.Equal(t, []string{"IKO"}, getSetCodes(t, dataStore))
+		})
+	}
+}
+
+func TestNextRound_round_duration(t *testing.T) {
+	tests := []struct {
+		name          string
+		roundDuration time.Duration
+		expected      time.Duration
+	}{
+		{name: "stored", roundDuration: 48 * time.Hour, expected: 48 * time.Hour},
+		{name: "none", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataStore := newRoundDataStore(t, repository.Pairing{Round: 0, Player1: "a", Player2: "b", Wins1: 2, Wins2: 1})
+			league, err := dataStore.GetLeague()
+			require.NoError(t, err)
+			league.RoundDurationSeconds = int(tt.roundDuration.Seconds())
+			require.NoError(t, dataStore.UpdateLeague(league))
+			// the configured round duration has changed since the league started
+			config := DefaultConfig()
+			config.RoundDuration = time.Hour
+			manager := NewLeagueManager(dataStore, nil, nil, config)
+			ctx := WithCapabilities(context.Background(), []Capability{CapabilityStart})
+
+			_, err = manager.NextRound(ctx, "admin", "M21")
+			require.NoError(t, err)
+			assertDeadline(t, manager, tt.expected)
+
+			_, err = manager.RollbackRound(ctx, "admin")
+			require.NoError(t, err)
+			assertDeadline(t, manager, tt.expected)
+		})
+	}
+}
+
+// assertDeadline asserts that the current round ends after the given duration from now. A zero duration means the round has no deadline.
+func assertDeadline(t *testing.T, manager *Manager, expected time.Duration) {
+	t.Helper()
+
+	deadline, found, err := manager.GetRoundDeadline()
+	require.NoError(t, err)
+	if expected == 0 {
+		assert.False(t, found)
+		return
+	}
+
+	require.True(t, found)
+	assert.WithinDuration(t, time.Now().Add(expected), deadline, time.Minute)
+}
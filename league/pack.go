@@ -0,0 +1,151 @@
+package league
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"progression/packGenerator"
+	"progression/repository"
+	"progression/scryfall"
+	"strings"
+)
+
+// Pack is a single opened booster pack. Its cards are grouped by rarity slot, keeping the order of the pack generator within each slot.
+type Pack struct {
+	Set   string
+	Slots map[repository.Rarity][]repository.Card
+}
+
+// Cards returns all cards in the pack, from the lowest to the highest rarity.
+func (p Pack) Cards() []repository.Card {
+	var cards []repository.Card
+	for _, rarity := range repository.Rarities {
+		cards = append(cards, p.Slots[rarity]...)
+	}
+	return cards
+}
+
+// openPacks generates the given number of packs of the set at once and splits them up, so they can be revealed separately.
+// The rarities are looked up on scryfall. If that fails, the rarities known to the pack generator are used instead.
+func (m *Manager) openPacks(ctx context.Context, set string, count int) ([]Pack, error) {
+	generatedCards, err := m.mbpgClient.GetPacks(set, count)
+	if err != nil {
+		return nil, err
+	}
+
+	rarities := generatorRarities(generatedCards)
+	cards := convertCardsFormat(generatedCards)
+	printings := make([]scryfall.Printing, 0, len(cards))
+	for _, card := range cards {
+		printings = append(printings, scryfall.Printing{SetCode: card.Set, CollectorNumber: card.CollectorNumber})
+	}
+
+	scryfallRarities, err := m.lookupRarities(ctx, printings)
+	if err != nil {
+		slog.Warn("Failed to look up rarities, using the pack generator's", "set", set, "error", err)
+	}
+	maps.Copy(rarities, scryfallRarities)
+
+	return splitPacks(strings.ToUpper(set), cards, count, rarities), nil
+}
+
+// generatorRarities returns the rarity of each of the generated cards, which the pack generator knows the rarity of, keyed by printingKey.
+func generatorRarities(cards []packGenerator.Card) map[string]repository.Rarity {
+	rarities := make(map[string]repository.Rarity, len(cards))
+	for _, card := range cards {
+		if card.Rarity != "" {
+			rarities[printingKey(card.Set, card.CollectorNumber)] = convertRarity(card.Rarity)
+		}
+	}
+	return rarities
+}
+
+// splitPacks splits the cards of the given number of packs, which the pack generator returns one after another, into packs of equal size.
+// Leftover cards are added to the last pack.
+func splitPacks(set string, cards []repository.Card, count int, rarities map[string]repository.Rarity) []Pack {
+	if count <= 0 {
+		return nil
+	}
+
+	size := len(cards) / count
+	packs := make([]Pack, count)
+	for i := range packs {
+		end := (i + 1) * size
+		if i == count-1 {
+			end = len(cards)
+		}
+		packs[i] = groupByRarity(set, cards[i*size:end], rarities)
+	}
+
+	return packs
+}
+
+// lookupRarities returns the rarity of each of the given printings, keyed by printingKey.
+func (m *Manager) lookupRarities(ctx context.Context, printings []scryfall.Printing) (map[string]repository.Rarity, error) {
+	unique := make([]scryfall.Printing, 0, len(printings))
+	seen := make(map[string]bool, len(printings))
+	for _, printing := range printings {
+		key := printingKey(printing.SetCode, printing.CollectorNumber)
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, printing)
+		}
+	}
+
+	sfCards, err := m.scryfallClient.GetCards(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	rarities := make(map[string]repository.Rarity, len(sfCards))
+	for _, sfCard := range sfCards {
+		rarities[printingKey(sfCard.Set, sfCard.CollectorNumber)] = convertRarity(sfCard.Rarity)
+	}
+
+	return rarities, nil
+}
+
+// groupByRarity sorts the cards of a pack into their rarity slots.
+// Cards, whose rarity is unknown, are treated as commons, since they must not prevent the pack from being opened.
+func groupByRarity(set string, cards []repository.Card, rarities map[string]repository.Rarity) Pack {
+	pack := Pack{
+		Set:   set,
+		Slots: make(map[repository.Rarity][]repository.Card),
+	}
+
+	for _, card := range cards {
+		rarity, found := rarities[printingKey(card.Set, card.CollectorNumber)]
+		if !found {
+			rarity = repository.RarityCommon
+		}
+		pack.Slots[rarity] = append(pack.Slots[rarity], card)
+	}
+
+	return pack
+}
+
+// flattenPacks returns the cards of all given packs.
+func flattenPacks(packs []Pack) []repository.Card {
+	var cards []repository.Card
+	for _, pack := range packs {
+		cards = append(cards, pack.Cards()...)
+	}
+	return cards
+}
+
+// packRarities returns the rarity of each card of the given packs, keyed by printingKey, as determined when the packs were opened.
+func packRarities(packs []Pack) map[string]repository.Rarity {
+	rarities := make(map[string]repository.Rarity)
+	for _, pack := range packs {
+		for rarity, cards := range pack.Slots {
+			for _, card := range cards {
+				rarities[printingKey(card.Set, card.CollectorNumber)] = rarity
+			}
+		}
+	}
+	return rarities
+}
+
+func printingKey(setCode, collectorNumber string) string {
+	return strings.ToUpper(setCode) + "|" + collectorNumber
+}
@@ -0,0 +1,63 @@
+package league
+
+import (
+	"progression/packGenerator"
+	"progression/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupByRarity(t *testing.T) {
+	cards := []repository.Card{
+		{Name: "Adaptive Shimmerer", Set: "iko", CollectorNumber: "1", Count: 1},
+		{Name: "Huntmaster Liger", Set: "iko", CollectorNumber: "16", Count: 1},
+		{Name: "Farfinder", Set: "iko", CollectorNumber: "2", Count: 1},
+		{Name: "Lurrus of the Dream-Den", Set: "iko", CollectorNumber: "226", Foil: true, Count: 1},
+		{Name: "Unknown Card", Set: "iko", CollectorNumber: "999", Count: 1},
+	}
+	rarities := map[string]repository.Rarity{
+		printingKey("IKO", "1"):   repository.RarityCommon,
+		printingKey("IKO", "16"):  repository.RarityUncommon,
+		printingKey("IKO", "2"):   repository.RarityCommon,
+		printingKey("IKO", "226"): repository.RarityRare,
+	}
+
+	pack := groupByRarity("IKO", cards, rarities)
+	assert.Equal(t, "IKO", pack.Set)
+	assert.Equal(t, []repository.Card{cards[0], cards[2], cards[4]}, pack.Slots[repository.RarityCommon], "commons did not match")
+	assert.Equal(t, []repository.Card{cards[1]}, pack.Slots[repository.RarityUncommon], "uncommons did not match")
+	assert.Equal(t, []repository.Card{cards[3]}, pack.Slots[repository.RarityRare], "rares did not match")
+	assert.Empty(t, pack.Slots[repository.RarityMythic])
+	assert.Equal(t, []repository.Card{cards[0], cards[2], cards[4], cards[1], cards[3]}, pack.Cards(), "cards should be ordered by rarity")
+}
+
+func TestSplitPacks(t *testing.T) {
+	cards := []repository.Card{
+		{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 1},
+		{Name: "Huntmaster Liger", Set: "IKO", CollectorNumber: "16", Count: 1},
+		{Name: "Farfinder", Set: "IKO", CollectorNumber: "2", Count: 1},
+		{Name: "Lurrus of the Dream-Den", Set: "IKO", CollectorNumber: "226", Count: 1},
+		{Name: "Unknown Card", Set: "IKO", CollectorNumber: "999", Count: 1},
+	}
+	rarities := map[string]repository.Rarity{printingKey("IKO", "16"): repository.RarityUncommon}
+
+	packs := splitPacks("IKO", cards, 2, rarities)
+	assert.Len(t, packs, 2)
+	assert.Equal(t, []repository.Card{cards[0], cards[1]}, packs[0].Cards(), "first pack did not match")
+	assert.Equal(t, []repository.Card{cards[1]}, packs[0].Slots[repository.RarityUncommon], "uncommons did not match")
+	assert.Equal(t, []repository.Card{cards[2], cards[3], cards[4]}, packs[1].Cards(), "expected the leftover card in the last pack")
+}
+
+func TestGeneratorRarities(t *testing.T) {
+	cards := []packGenerator.Card{
+		{Name: "Adaptive Shimmerer", Set: "iko", CollectorNumber: "1", Rarity: "common"},
+		{Name: "Lurrus of the Dream-Den", Set: "iko", CollectorNumber: "226", Rarity: "rare"},
+		{Name: "Unknown Card", Set: "iko", CollectorNumber: "999"},
+	}
+
+	assert.Equal(t, map[string]repository.Rarity{
+		printingKey("IKO", "1"):   repository.RarityCommon,
+		printingKey("IKO", "226"): repository.RarityRare,
+	}, generatorRarities(cards))
+}
@@ -0,0 +1,26 @@
+package league
+
+import (
+	"math/rand/v2"
+	"progression/repository"
+)
+
+// generatePairings randomly pairs the given players for the given round.
+// If the number of players is uneven, one player remains without an opponent.
+func generatePairings(players []repository.Player, round int) []repository.Pairing {
+	shuffled := make([]repository.Player, len(players))
+	copy(shuffled, players)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	pairings := make([]repository.Pairing, 0, len(shuffled)/2)
+	for i := 0; i+1 < len(shuffled); i += 2 {
+		pairings = append(pairings, repository.Pairing{
+			Round:   round,
+			Player1: shuffled[i].Id,
+			Player2: shuffled[i+1].Id,
+		})
+	}
+	return pairings
+}
@@ -0,0 +1,24 @@
+package league
+
+import (
+	"progression/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratePairings(t *testing.T) {
+	players := []repository.Player{{Id: "a"}, {Id: "b"}, {Id: "c"}, {Id: "d"}, {Id: "e"}}
+
+	pairings := generatePairings(players, 2)
+	assert.Len(t, pairings, 2, "expected one player without an opponent")
+
+	paired := make(map[string]bool)
+	for _, pairing := range pairings {
+		assert.Equal(t, 2, pairing.Round, "round did not match")
+		assert.NotEqual(t, pairing.Player1, pairing.Player2, "player paired with themselves")
+		assert.False(t, paired[pairing.Player1] || paired[pairing.Player2], "player paired twice")
+		paired[pairing.Player1] = true
+		paired[pairing.Player2] = true
+	}
+}
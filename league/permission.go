@@ -0,0 +1,50 @@
+package league
+
+import (
+	"context"
+	"slices"
+)
+
+// Capability is the permission to perform a kind of administrative action.
+type Capability string
+
+const (
+	// CapabilityStart allows starting a league.
+	CapabilityStart Capability = "start"
+	// CapabilityBan allows banning and unbanning cards.
+	CapabilityBan Capability = "ban"
+	// CapabilityAdjust allows granting and revoking wild cards, wild packs and cards.
+	CapabilityAdjust Capability = "adjust"
+	// CapabilityReport allows reporting matches on behalf of players, e.g. to resolve matches flagged at a round's deadline.
+	CapabilityReport Capability = "report"
+)
+
+// Capabilities contains all capabilities.
+var Capabilities = []Capability{CapabilityStart, CapabilityBan, CapabilityAdjust, CapabilityReport}
+
+type capabilitiesKey struct{}
+
+// WithCapabilities returns a context granting the given capabilities to the user performing actions with it, e.g. based on their discord roles.
+func WithCapabilities(ctx context.Context, capabilities []Capability) context.Context {
+	return context.WithValue(ctx, capabilitiesKey{}, capabilities)
+}
+
+// requireCapability returns ErrPlayerNotAdmin, unless the context grants the capability to the given user or the user is an admin.
+// Admins have every capability.
+func (m *Manager) requireCapability(ctx context.Context, userID string, capability Capability) error {
+	capabilities, _ := ctx.Value(capabilitiesKey{}).([]Capability)
+	if slices.Contains(capabilities, capability) {
+		return nil
+	}
+
+	isAdmin, err := m.dataStore.IsAdmin(userID)
+	if err != nil {
+		return err
+	}
+
+	if !isAdmin {
+		return ErrPlayerNotAdmin
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package league
+
+import (
+	"progression/repository"
+	"strconv"
+)
+
+// addCards adds the given cards, each being a single copy, to the player's card pool and records the change in their pool ledger.
+// Both are stored together or not at all.
+func (m *Manager) addCards(userID string, cards []repository.Card, reason repository.LedgerReason, round int, actor string) error {
+	if len(cards) == 0 {
+		return nil
+	}
+
+	return m.withTx(func(tx *Manager) error {
+		err := tx.dataStore.StoreCards(userID, cards)
+		if err != nil {
+			return err
+		}
+
+		return tx.dataStore.AppendPoolEntries(newPoolEntries(userID, cards, reason, round, actor))
+	})
+}
+
+// removeCards removes the given number of copies of the card from the player's card pool and records the change in their pool ledger.
+// Both are stored together or not at all.
+func (m *Manager) removeCards(userID string, card repository.Card, count int, reason repository.LedgerReason, round int, actor string) error {
+	return m.withTx(func(tx *Manager) error {
+		err := tx.dataStore.RemoveCards(userID, card, count)
+		if err != nil {
+			return err
+		}
+
+		entry := newPoolEntry(userID, card, -count, reason, round, actor)
+		return tx.dataStore.AppendPoolEntries([]repository.PoolEntry{entry})
+	})
+}
+
+// newPoolEntries returns one pool entry for every printing among the given cards, each being a single copy, in the order of their first copy.
+func newPoolEntries(userID string, cards []repository.Card, reason repository.LedgerReason, round int, actor string) []repository.PoolEntry {
+	var entries []repository.PoolEntry
+	indices := make(map[string]int)
+	for _, card := range cards {
+		// foil and non-foil copies are recorded separately
+		key := printingKey(card.Set, card.CollectorNumber) + "|" + strconv.FormatBool(card.Foil)
+		idx, found := indices[key]
+		if !found {
+			indices[key] = len(entries)
+			entries = append(entries, newPoolEntry(userID, card, 1, reason, round, actor))
+			continue
+		}
+
+		entries[idx].Count++
+	}
+
+	return entries
+}
+
+func newPoolEntry(userID string, card repository.Card, count int, reason repository.LedgerReason, round int, actor string) repository.PoolEntry {
+	return repository.PoolEntry{
+		PlayerId:        userID,
+		Name:            card.Name,
+		Set:             card.Set,
+		CollectorNumber: card.CollectorNumber,
+		Foil:            card.Foil,
+		ScryfallURI:     card.ScryfallURI,
+		ImageURL:        card.ImageURL,
+		Count:           count,
+		Reason:          reason,
+		Round:           round,
+		Actor:           actor,
+	}
+}
@@ -0,0 +1,199 @@
+package league
+
+import (
+	"context"
+	"fmt"
+	"progression/repository"
+	"strconv"
+	"time"
+)
+
+// RollbackRound reverts the active league to its previous round, e.g. after a round has been started by mistake.
+// Every change to the balances and card pools recorded during the current round is reverted, the sets unlocked by it are locked again and its pairings are removed.
+// The previous round is scheduled anew. It returns the previous round.
+func (m *Manager) RollbackRound(ctx context.Context, userID string) (int, error) {
+	const errMsg = "failed to roll back round: %w"
+
+	err := m.requireCapability(ctx, userID, CapabilityStart)
+	if err != nil {
+		return 0, fmt.Errorf(errMsg, err)
+	}
+
+	round, err := m.dataStore.GetRound()
+	if err != nil {
+		return 0, fmt.Errorf(errMsg, err)
+	}
+
+	if round == firstRound {
+		return 0, fmt.Errorf(errMsg, ErrNoPreviousRound)
+	}
+
+	var previousRound int
+	err = m.withTx(func(tx *Manager) error {
+		err := tx.revertBalances(round, userID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.revertCardPools(round, userID)
+		if err != nil {
+			return err
+		}
+
+		setCodes, err := tx.lockRoundSets(round)
+		if err != nil {
+			return err
+		}
+
+		err = tx.dataStore.DeletePairings(round)
+		if err != nil {
+			return err
+		}
+
+		previousRound, err = tx.dataStore.PreviousRound()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.scheduleRound(time.Now())
+		if err != nil {
+			return err
+		}
+
+		tx.emit(RoundRolledBack{Round: round, SetCodes: setCodes})
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf(errMsg, err)
+	}
+
+	return previousRound, nil
+}
+
+// revertBalances records a rollback entry for every player and currency, whose balance changed during the given round, reverting the net change.
+// Previous rollbacks of the round are included in the net change, so a round can be rolled back again after it has been restarted.
+func (m *Manager) revertBalances(round int, actor string) error {
+	entries, err := m.dataStore.GetRoundLedgerEntries(round)
+	if err != nil {
+		return err
+	}
+
+	// the changes are kept in the order of their first entry, so the rollback entries are recorded in a stable order
+	changes := make(map[string]map[repository.Currency]int)
+	currencies := make(map[string][]repository.Currency)
+	var playerIDs []string
+	for _, entry := range entries {
+		if changes[entry.PlayerId] == nil {
+			changes[entry.PlayerId] = make(map[repository.Currency]int)
+			playerIDs = append(playerIDs, entry.PlayerId)
+		}
+		if _, found := changes[entry.PlayerId][entry.Currency]; !found {
+			currencies[entry.PlayerId] = append(currencies[entry.PlayerId], entry.Currency)
+		}
+		changes[entry.PlayerId][entry.Currency] += entry.Amount
+	}
+
+	note := "rollback of round " + strconv.Itoa(round)
+	for _, playerID := range playerIDs {
+		player, err := m.dataStore.GetPlayerForUpdate(playerID)
+		if err != nil {
+			return err
+		}
+
+		var playerEntries []repository.LedgerEntry
+		for _, currency := range currencies[playerID] {
+			amount := changes[playerID][currency]
+			if amount == 0 {
+				continue
+			}
+
+			entry := changeBalance(&player, currency, -amount, repository.LedgerReasonRollback, round, actor)
+			entry.Note = note
+			playerEntries = append(playerEntries, entry)
+		}
+
+		if len(playerEntries) == 0 {
+			continue
+		}
+
+		err = m.updatePlayer(player, playerEntries)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revertCardPools adds or removes copies of every printing, whose number of copies in a player's card pool changed during the given round, reverting the net change.
+func (m *Manager) revertCardPools(round int, actor string) error {
+	entries, err := m.dataStore.GetRoundPoolEntries(round)
+	if err != nil {
+		return err
+	}
+
+	var changes []repository.PoolEntry
+	indices := make(map[string]int)
+	for _, entry := range entries {
+		key := entry.PlayerId + "|" + printingKey(entry.Set, entry.CollectorNumber) + "|" + strconv.FormatBool(entry.Foil)
+		idx, found := indices[key]
+		if !found {
+			indices[key] = len(changes)
+			changes = append(changes, entry)
+			continue
+		}
+
+		changes[idx].Count += entry.Count
+	}
+
+	for _, change := range changes {
+		card := repository.Card{
+			Name:            change.Name,
+			Set:             change.Set,
+			CollectorNumber: change.CollectorNumber,
+			Foil:            change.Foil,
+			ScryfallURI:     change.ScryfallURI,
+			ImageURL:        change.ImageURL,
+			Count:           1,
+		}
+
+		switch {
+		case change.Count > 0:
+			err = m.removeCards(change.PlayerId, card, change.Count, repository.LedgerReasonRollback, round, actor)
+		case change.Count < 0:
+			copies := make([]repository.Card, -change.Count)
+			for i := range copies {
+				copies[i] = card
+			}
+			err = m.addCards(change.PlayerId, copies, repository.LedgerReasonRollback, round, actor)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lockRoundSets locks the sets unlocked when the given round started and returns their set codes.
+func (m *Manager) lockRoundSets(round int) ([]string, error) {
+	sets, err := m.dataStore.GetSets()
+	if err != nil {
+		return nil, err
+	}
+
+	var setCodes []string
+	for _, set := range sets {
+		if set.Round != round {
+			continue
+		}
+
+		err = m.dataStore.LockSet(set.SetCode)
+		if err != nil {
+			return nil, err
+		}
+		setCodes = append(setCodes, set.SetCode)
+	}
+
+	return setCodes, nil
+}
@@ -0,0 +1,109 @@
+package league
+
+import (
+	"context"
+	"progression/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollbackRound(t *testing.T) {
+	dataStore := newRoundDataStore(t, repository.Pairing{Round: 0, Player1: "a", Player2: "b", Wins1: 2, Wins2: 1})
+	manager := NewLeagueManager(dataStore, nil, nil, DefaultConfig())
+	var published []Event
+	manager.Events().Subscribe(func(event Event) {
+		published = append(published, event)
+	})
+
+	ctx := WithCapabilities(context.Background(), []Capability{CapabilityStart})
+	// the round is rolled back twice to make sure the rollback of the first attempt is taken into account
+	for range 2 {
+		_, err := manager.NextRound(ctx, "admin", "M21")
+		require.NoError(t, err)
+
+		card := repository.Card{Name: "Lurrus of the Dream-Den", Set: "M21", CollectorNumber: "226", Count: 1}
+		err = manager.addCards("a", []repository.Card{card, card}, repository.LedgerReasonRedemption, 1, "a")
+		require.NoError(t, err)
+
+		round, err := manager.RollbackRound(ctx, "admin")
+		require.NoError(t, err)
+		assert.Equal(t, 0, round)
+	}
+
+	assert.Equal(t, 0, getRound(t, dataStore))
+	assert.Equal(t, []string{"IKO"}, getSetCodes(t, dataStore))
+	assert.Equal(t, 1, getPlayer(t, dataStore, "a").WildPacks)
+	assert.Equal(t, 0, getPlayer(t, dataStore, "b").WildPacks)
+	pairings, err := dataStore.GetAllPairings()
+	require.NoError(t, err)
+	assert.Len(t, pairings, 1)
+
+	rollbackEntries := 0
+	for _, entry := range getLedgerEntries(t, dataStore, "a", "b") {
+		if entry.Reason == repository.LedgerReasonRollback {
+			rollbackEntries++
+			assert.Equal(t, -roundWildPacks, entry.Amount)
+			assert.Equal(t, 1, entry.Round)
+		}
+	}
+	assert.Equal(t, 4, rollbackEntries, "expected every player's packs to be reverted in both rollbacks")
+
+	poolEntries, err := dataStore.GetPoolEntries("a")
+	require.NoError(t, err)
+	poolCount := 0
+	for _, entry := range poolEntries {
+		poolCount += entry.Count
+	}
+	assert.Equal(t, 0, poolCount, "expected the added cards to be removed")
+	assert.Equal(t, -2, poolEntries[len(poolEntries)-1].Count)
+
+	manager.Events().Wait()
+	require.NotEmpty(t, published)
+	assert.Equal(t, RoundRolledBack{Round: 1, SetCodes: []string{"M21"}}, published[len(published)-1])
+}
+
+func TestRollbackRound_earlier_league(t *testing.T) {
+	dataStore := newRoundDataStore(t, repository.Pairing{Round: 1, Player1: "c", Player2: "d"})
+	require.NoError(t, dataStore.EndLeague())
+	require.NoError(t, dataStore.StartLeague())
+	_, err := dataStore.NextRound()
+	require.NoError(t, err)
+	require.NoError(t, dataStore.StorePairings([]repository.Pairing{{Round: 1, Player1: "a", Player2: "b"}}))
+	manager := NewLeagueManager(dataStore, nil, nil, DefaultConfig())
+
+	_, err = manager.RollbackRound(WithCapabilities(context.Background(), []Capability{CapabilityStart}), "admin")
+	require.NoError(t, err)
+
+	pairings, err := dataStore.GetAllPairings()
+	require.NoError(t, err)
+	require.Len(t, pairings, 1, "expected only the pairing of the rolled back round to be removed")
+	assert.Equal(t, "c", pairings[0].Player1, "expected the pairing of the earlier league to be kept")
+}
+
+func TestRollbackRound_errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities []Capability
+		round        int
+		expected     error
+	}{
+		{name: "not_admin", round: 1, expected: ErrPlayerNotAdmin},
+		{name: "first_round", capabilities: []Capability{CapabilityStart}, round: 0, expected: ErrNoPreviousRound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataStore := newRoundDataStore(t, repository.Pairing{Round: tt.round, Player1: "a", Player2: "b"})
+			manager := NewLeagueManager(dataStore, nil, nil, DefaultConfig())
+
+			_, err := manager.RollbackRound(WithCapabilities(context.Background(), tt.capabilities), "admin")
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Equal(t, tt.round, getRound(t, dataStore))
+			pairings, err := dataStore.GetAllPairings()
+			require.NoError(t, err)
+			assert.Len(t, pairings, 1)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package league
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"progression/repository"
+	"time"
+)
+
+// schedulerInterval is the time between two checks of the current round's deadline.
+const schedulerInterval = time.Minute
+
+// deadlineDraws is the number of drawn games reported for matches, which haven't been reported by the deadline.
+const deadlineDraws = 3
+
+// Scheduler reminds players of the current round's deadline and resolves unreported matches once it has passed.
+// The deadline is read from the datastore on every check, so the scheduler picks up where it left off after a restart.
+// Reminders and resolved matches are published as DeadlineReminder and DeadlinePassed events.
+type Scheduler struct {
+	manager *Manager
+}
+
+func NewScheduler(manager *Manager) *Scheduler {
+	return &Scheduler{
+		manager: manager,
+	}
+}
+
+// Run checks the current round's deadline every schedulerInterval until the context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			err := s.manager.checkDeadline(now)
+			if err != nil {
+				slog.Error("Failed to check the round's deadline", "error", err)
+			}
+		}
+	}
+}
+
+// checkDeadline resolves the current round's unreported matches according to the deadline policy, if the deadline has passed.
+// Otherwise, it reminds the players of unreported matches, if the last reminder is at least ReminderInterval ago.
+func (m *Manager) checkDeadline(now time.Time) error {
+	const errMsg = "failed to check deadline: %w"
+
+	err := m.withTx(func(tx *Manager) error {
+		league, err := tx.dataStore.GetLeague()
+		if errors.Is(err, repository.ErrNoActiveLeague) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if league.RoundDeadline == nil {
+			return nil
+		}
+
+		pairings, err := tx.dataStore.GetPairings(league.Round)
+		if err != nil {
+			return err
+		}
+
+		unreported := unreportedPairings(pairings)
+		if !now.Before(*league.RoundDeadline) {
+			resolved, err := tx.resolveUnreported(league.Round, unreported)
+			if err != nil {
+				return err
+			}
+
+			league.RoundDeadline = nil
+			err = tx.dataStore.UpdateLeague(league)
+			if err != nil {
+				return err
+			}
+
+			if len(resolved) != 0 {
+				tx.emit(DeadlinePassed{Round: league.Round, Policy: m.config.DeadlinePolicy, Pairings: resolved})
+			}
+			return nil
+		}
+
+		if len(unreported) == 0 || !isReminderDue(league, now, m.config.ReminderInterval) {
+			return nil
+		}
+
+		league.RemindedAt = &now
+		err = tx.dataStore.UpdateLeague(league)
+		if err != nil {
+			return err
+		}
+
+		tx.emit(DeadlineReminder{Round: league.Round, Deadline: *league.RoundDeadline, Pairings: unreported})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf(errMsg, err)
+	}
+
+	return nil
+}
+
+// resolveUnreported reports the pairings as draws or flags them for the admins, depending on the deadline policy.
+// It returns the resolved pairings.
+func (m *Manager) resolveUnreported(round int, pairings []repository.Pairing) ([]repository.Pairing, error) {
+	resolved := make([]repository.Pairing, 0, len(pairings))
+	for _, pairing := range pairings {
+		var err error
+		switch m.config.DeadlinePolicy {
+		case DeadlinePolicyDraw:
+			pairing.Draws = deadlineDraws
+			err = m.dataStore.UpdatePairing(pairing)
+		default:
+			pairing.Flagged = true
+			err = m.dataStore.FlagPairing(pairing)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !pairing.Flagged {
+			m.emit(MatchReported{Pairing: pairing, ReporterID: systemActor})
+		}
+		resolved = append(resolved, pairing)
+	}
+
+	if len(resolved) == 0 || m.config.DeadlinePolicy != DeadlinePolicyDraw {
+		return resolved, nil
+	}
+
+	return resolved, m.awardRewardsIfRoundOver(round)
+}
+
+// scheduleLeague stores the configured round duration on the active league, which has just started, and sets the deadline of its first round.
+// Later changes to the configured round duration only apply to leagues started afterwards. It returns the deadline, if any.
+func (m *Manager) scheduleLeague(now time.Time) (*time.Time, error) {
+	if m.config.RoundDuration <= 0 {
+		return nil, nil
+	}
+
+	league, err := m.dataStore.GetLeague()
+	if err != nil {
+		return nil, err
+	}
+
+	league.RoundDurationSeconds = int(m.config.RoundDuration.Seconds())
+	return m.setDeadline(league, now)
+}
+
+// scheduleRound sets the deadline of the active league's current round, which starts now, using the round duration stored on the league.
+// Rounds have no deadline, if the league has no round duration. It returns the deadline, if any.
+func (m *Manager) scheduleRound(now time.Time) (*time.Time, error) {
+	league, err := m.dataStore.GetLeague()
+	if err != nil {
+		return nil, err
+	}
+
+	if league.RoundDuration() <= 0 {
+		return nil, nil
+	}
+
+	return m.setDeadline(league, now)
+}
+
+// setDeadline stores the league with the deadline of its current round, which starts now.
+func (m *Manager) setDeadline(league repository.League, now time.Time) (*time.Time, error) {
+	deadline := now.Add(league.RoundDuration())
+	league.RoundDeadline = &deadline
+	league.RemindedAt = &now
+	return &deadline, m.dataStore.UpdateLeague(league)
+}
+
+// GetRoundDeadline returns the deadline of the current round. It returns false, if the round has no time limit or the deadline has passed.
+func (m *Manager) GetRoundDeadline() (time.Time, bool, error) {
+	const errMsg = "failed to get round deadline: %w"
+
+	league, err := m.dataStore.GetLeague()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf(errMsg, err)
+	}
+
+	if league.RoundDeadline == nil {
+		return time.Time{}, false, nil
+	}
+
+	return *league.RoundDeadline, true, nil
+}
+
+// unreportedPairings returns the pairings, which have neither been reported nor flagged yet.
+func unreportedPairings(pairings []repository.Pairing) []repository.Pairing {
+	var unreported []repository.Pairing
+	for _, pairing := range pairings {
+		if !isMatchReported(pairing) && !pairing.Flagged {
+			unreported = append(unreported, pairing)
+		}
+	}
+	return unreported
+}
+
+// isReminderDue returns whether the players have last been reminded at least the given interval ago. A zero interval disables reminders.
+func isReminderDue(league repository.League, now time.Time, interval time.Duration) bool {
+	if interval <= 0 {
+		return false
+	}
+
+	return league.RemindedAt == nil || now.Sub(*league.RemindedAt) >= interval
+}
@@ -0,0 +1,44 @@
+package league
+
+import (
+	"progression/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnreportedPairings(t *testing.T) {
+	pairings := []repository.Pairing{
+		{Player1: "a", Player2: "b", Wins1: 2, Wins2: 1},
+		{Player1: "c", Player2: "d"},
+		{Player1: "e", Player2: "f", Flagged: true},
+		{Player1: "g", Player2: "h", Draws: deadlineDraws},
+	}
+
+	assert.Equal(t, []repository.Pairing{pairings[1]}, unreportedPairings(pairings))
+}
+
+func TestIsReminderDue(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	hourAgo := now.Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		remindedAt *time.Time
+		interval   time.Duration
+		expected   bool
+	}{
+		{name: "never reminded", interval: time.Hour, expected: true},
+		{name: "interval passed", remindedAt: &hourAgo, interval: time.Hour, expected: true},
+		{name: "interval not passed", remindedAt: &hourAgo, interval: 2 * time.Hour, expected: false},
+		{name: "reminders disabled", interval: 0, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			league := repository.League{RemindedAt: tt.remindedAt}
+			assert.Equal(t, tt.expected, isReminderDue(league, now, tt.interval))
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package league
+
+import (
+	"cmp"
+	"fmt"
+	"progression/repository"
+	"slices"
+)
+
+const (
+	pointsPerWin  = 3
+	pointsPerDraw = 1
+)
+
+// Standing is a player's record over all reported matches of the league.
+type Standing struct {
+	PlayerID    string
+	Points      int
+	MatchWins   int
+	MatchLosses int
+	MatchDraws  int
+	GameWins    int
+	GameLosses  int
+	GameDraws   int
+	Dropped     bool
+}
+
+// GetStandings returns the standings of every player, including dropped players, ordered by points, game win difference and player ID.
+func (m *Manager) GetStandings() ([]Standing, error) {
+	const errMsg = "failed to get standings: %w"
+
+	round, err := m.dataStore.GetRound()
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	players, err := m.dataStore.GetAllPlayers()
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	droppedPlayers, err := m.dataStore.GetDroppedPlayers()
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+	players = append(players, droppedPlayers...)
+
+	var pairings []repository.Pairing
+	for r := firstRound; r <= round; r++ {
+		roundPairings, err := m.dataStore.GetPairings(r)
+		if err != nil {
+			return nil, fmt.Errorf(errMsg, err)
+		}
+		pairings = append(pairings, roundPairings...)
+	}
+
+	return calculateStandings(players, pairings), nil
+}
+
+// GetPairings returns the pairings of the given round.
+func (m *Manager) GetPairings(round int) ([]repository.Pairing, error) {
+	const errMsg = "failed to get pairings: %w"
+
+	pairings, err := m.dataStore.GetPairings(round)
+	if err != nil {
+		return nil, fmt.Errorf(errMsg, err)
+	}
+
+	return pairings, nil
+}
+
+// GetRound returns the current round of the active league.
+func (m *Manager) GetRound() (int, error) {
+	const errMsg = "failed to get round: %w"
+
+	round, err := m.dataStore.GetRound()
+	if err != nil {
+		return 0, fmt.Errorf(errMsg, err)
+	}
+
+	return round, nil
+}
+
+// calculateStandings sums up the results of the reported pairings for every player.
+func calculateStandings(players []repository.Player, pairings []repository.Pairing) []Standing {
+	standings := make(map[string]*Standing, len(players))
+	for _, player := range players {
+		standings[player.Id] = &Standing{PlayerID: player.Id, Dropped: player.Dropped}
+	}
+
+	for _, pairing := range pairings {
+		if !isMatchReported(pairing) {
+			continue
+		}
+
+		addResult(standings, pairing.Player1, pairing.Wins1, pairing.Wins2, pairing.Draws)
+		addResult(standings, pairing.Player2, pairing.Wins2, pairing.Wins1, pairing.Draws)
+	}
+
+	result := make([]Standing, 0, len(standings))
+	for _, standing := range standings {
+		result = append(result, *standing)
+	}
+
+	slices.SortFunc(result, func(a, b Standing) int {
+		return cmp.Or(
+			cmp.Compare(b.Points, a.Points),
+			cmp.Compare(b.GameWins-b.GameLosses, a.GameWins-a.GameLosses),
+			cmp.Compare(a.PlayerID, b.PlayerID),
+		)
+	})
+	return result
+}
+
+// addResult adds a match result from the point of view of the given player.
+func addResult(standings map[string]*Standing, playerID string, wins, losses, draws int) {
+	standing, found := standings[playerID]
+	if !found {
+		return
+	}
+
+	standing.GameWins += wins
+	standing.GameLosses += losses
+	standing.GameDraws += draws
+
+	switch {
+	case wins > losses:
+		standing.MatchWins++
+		standing.Points += pointsPerWin
+	case wins < losses:
+		standing.MatchLosses++
+	default:
+		standing.MatchDraws++
+		standing.Points += pointsPerDraw
+	}
+}
@@ -0,0 +1,26 @@
+package league
+
+import (
+	"progression/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateStandings(t *testing.T) {
+	players := []repository.Player{{Id: "a"}, {Id: "b"}, {Id: "c"}, {Id: "d", Dropped: true}}
+	pairings := []repository.Pairing{
+		{Round: 0, Player1: "a", Player2: "b", Wins1: 2, Wins2: 1},
+		{Round: 0, Player1: "c", Player2: "d", Wins1: 2, Wins2: 0},
+		{Round: 1, Player1: "a", Player2: "c", Draws: 3},
+		{Round: 1, Player1: "b", Player2: "d"},
+	}
+
+	expected := []Standing{
+		{PlayerID: "c", Points: 4, MatchWins: 1, MatchDraws: 1, GameWins: 2, GameDraws: 3},
+		{PlayerID: "a", Points: 4, MatchWins: 1, MatchDraws: 1, GameWins: 2, GameLosses: 1, GameDraws: 3},
+		{PlayerID: "b", MatchLosses: 1, GameWins: 1, GameLosses: 2},
+		{PlayerID: "d", MatchLosses: 1, GameLosses: 2, Dropped: true},
+	}
+	assert.Equal(t, expected, calculateStandings(players, pairings))
+}
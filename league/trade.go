@@ -0,0 +1,374 @@
+package league
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"progression/repository"
+	"progression/scryfall"
+	"slices"
+	"strconv"
+)
+
+func (m *Manager) GetTrade(tradeID int) (repository.Trade, error) {
+	const errMsg = "failed to get trade: %w"
+
+	trade, err := m.dataStore.GetTrade(tradeID)
+	if err != nil {
+		return repository.Trade{}, fmt.Errorf(errMsg, err)
+	}
+
+	return trade, nil
+}
+
+// OfferTrade offers a trade from the proposer to the recipient. The given items change hands once the recipient accepts.
+// Every item has to be given by either the proposer or the recipient.
+func (m *Manager) OfferTrade(ctx context.Context, proposerID, recipientID string, items []repository.TradeItem) (repository.Trade, error) {
+	const errMsg = "failed to offer trade: %w"
+
+	if proposerID == recipientID || len(items) == 0 {
+		return repository.Trade{}, fmt.Errorf(errMsg, ErrInvalidTrade)
+	}
+
+	round, err := m.dataStore.GetRound()
+	if err != nil {
+		return repository.Trade{}, fmt.Errorf(errMsg, err)
+	}
+
+	for _, userID := range []string{proposerID, recipientID} {
+		_, err = m.getActivePlayer(userID)
+		if err != nil {
+			return repository.Trade{}, fmt.Errorf(errMsg, err)
+		}
+
+		err = m.checkTradeLimit(userID, round)
+		if err != nil {
+			return repository.Trade{}, fmt.Errorf(errMsg, err)
+		}
+	}
+
+	items, err = m.prepareTradeItems(ctx, proposerID, recipientID, items)
+	if err != nil {
+		return repository.Trade{}, fmt.Errorf(errMsg, err)
+	}
+
+	trade := repository.Trade{
+		Round:     round,
+		Proposer:  proposerID,
+		Recipient: recipientID,
+		Status:    repository.TradeStatusPending,
+		Items:     items,
+	}
+
+	trade.Id, err = m.dataStore.CreateTrade(trade)
+	if err != nil {
+		return repository.Trade{}, fmt.Errorf(errMsg, err)
+	}
+
+	return trade, nil
+}
+
+// CounterTrade declines a pending trade offered to the given user and offers the given items to the proposer instead.
+func (m *Manager) CounterTrade(ctx context.Context, userID string, tradeID int, items []repository.TradeItem) (repository.Trade, error) {
+	const errMsg = "failed to counter trade: %w"
+
+	trade, err := m.getPendingTrade(tradeID)
+	if err != nil {
+		return repository.Trade{}, fmt.Errorf(errMsg, err)
+	}
+
+	if trade.Recipient != userID {
+		return repository.Trade{}, fmt.Errorf(errMsg, ErrNotTradeRecipient)
+	}
+
+	var counterTrade repository.Trade
+	err = m.withTx(func(tx *Manager) error {
+		err := tx.dataStore.UpdateTradeStatus(tradeID, repository.TradeStatusPending, repository.TradeStatusCountered)
+		if err != nil {
+			return err
+		}
+
+		counterTrade, err = tx.OfferTrade(ctx, userID, trade.Proposer, items)
+		return err
+	})
+	if err != nil {
+		return repository.Trade{}, fmt.Errorf(errMsg, err)
+	}
+
+	return counterTrade, nil
+}
+
+// DeclineTrade declines a pending trade. The proposer can decline their own trade to withdraw it.
+func (m *Manager) DeclineTrade(userID string, tradeID int) (repository.Trade, error) {
+	const errMsg = "failed to decline trade: %w"
+
+	trade, err := m.getPendingTrade(tradeID)
+	if err != nil {
+		return repository.Trade{}, fmt.Errorf(errMsg, err)
+	}
+
+	if trade.Recipient != userID && trade.Proposer != userID {
+		return repository.Trade{}, fmt.Errorf(errMsg, ErrNotTradeRecipient)
+	}
+
+	err = m.dataStore.UpdateTradeStatus(tradeID, repository.TradeStatusPending, repository.TradeStatusDeclined)
+	if err != nil {
+		return repository.Trade{}, fmt.Errorf(errMsg, err)
+	}
+
+	trade.Status = repository.TradeStatusDeclined
+	return trade, nil
+}
+
+// AcceptTrade accepts a pending trade offered to the given user. Both card pools and balances are updated together or not at all.
+func (m *Manager) AcceptTrade(userID string, tradeID int) (repository.Trade, error) {
+	const errMsg = "failed to accept trade: %w"
+
+	trade, err := m.getPendingTrade(tradeID)
+	if err != nil {
+		return repository.Trade{}, fmt.Errorf(errMsg, err)
+	}
+
+	if trade.Recipient != userID {
+		return repository.Trade{}, fmt.Errorf(errMsg, ErrNotTradeRecipient)
+	}
+
+	round, err := m.dataStore.GetRound()
+	if err != nil {
+		return repository.Trade{}, fmt.Errorf(errMsg, err)
+	}
+
+	err = m.withTx(func(tx *Manager) error {
+		// both participants are locked in a fixed order, so trades between the same players can't deadlock
+		participantIDs := []string{trade.Proposer, trade.Recipient}
+		slices.Sort(participantIDs)
+		players := make(map[string]*repository.Player)
+		for _, participantID := range participantIDs {
+			player, err := tx.dataStore.GetPlayerForUpdate(participantID)
+			if err != nil {
+				return err
+			}
+
+			if player.Dropped {
+				return ErrPlayerAlreadyDropped
+			}
+			players[participantID] = &player
+
+			err = tx.checkTradeLimit(participantID, round)
+			if err != nil {
+				return err
+			}
+		}
+
+		// the limits are checked before the trade is accepted, so it doesn't count towards them itself
+		err := tx.dataStore.UpdateTradeStatus(tradeID, repository.TradeStatusPending, repository.TradeStatusAccepted)
+		if err != nil {
+			return err
+		}
+
+		// the offer is checked again, since the card pools might have changed since it was made
+		for _, item := range trade.Items {
+			if !item.IsCard() {
+				continue
+			}
+
+			owned, err := tx.countCopies(item.FromPlayer, item.Card())
+			if err != nil {
+				return err
+			}
+
+			if owned < item.Count {
+				return repository.ErrCardNotInPool
+			}
+		}
+
+		entries := make(map[string][]repository.LedgerEntry)
+		for _, item := range trade.Items {
+			from := players[item.FromPlayer]
+			to := players[otherParticipant(trade, item.FromPlayer)]
+
+			if item.IsCard() {
+				vaultEntries, err := tx.transferCards(from.Id, to, item, round, userID)
+				if err != nil {
+					return err
+				}
+				entries[to.Id] = append(entries[to.Id], vaultEntries...)
+				continue
+			}
+
+			if from.Balance(item.Currency) < item.Count {
+				return insufficientBalanceError(item.Currency)
+			}
+
+			note := "trade #" + strconv.Itoa(trade.Id)
+			debit := changeBalance(from, item.Currency, -item.Count, repository.LedgerReasonTrade, round, userID)
+			debit.Note = note
+			credit := changeBalance(to, item.Currency, item.Count, repository.LedgerReasonTrade, round, userID)
+			credit.Note = note
+			entries[from.Id] = append(entries[from.Id], debit)
+			entries[to.Id] = append(entries[to.Id], credit)
+		}
+
+		for playerID, playerEntries := range entries {
+			err = tx.updatePlayer(*players[playerID], playerEntries)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return repository.Trade{}, fmt.Errorf(errMsg, err)
+	}
+
+	trade.Status = repository.TradeStatusAccepted
+	m.emit(TradeAccepted{Trade: trade})
+	return trade, nil
+}
+
+// transferCards moves the copies of the card in the given item from one card pool to the other player's on behalf of the given actor.
+// Like with every other card, copies exceeding maxCopies are converted into vault progress, if the vault is enabled.
+// The caller is responsible for updating the receiving player and storing the returned ledger entries afterward.
+func (m *Manager) transferCards(fromID string, to *repository.Player, item repository.TradeItem, round int, actor string) ([]repository.LedgerEntry, error) {
+	err := m.removeCards(fromID, item.Card(), item.Count, repository.LedgerReasonTrade, round, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	copies := make([]repository.Card, item.Count)
+	for i := range copies {
+		copies[i] = item.Card()
+	}
+
+	// trades offered before rarities were stored on their items have no rarity, so their cards are treated as commons
+	rarities := make(map[string]repository.Rarity)
+	if item.Rarity != "" {
+		rarities[printingKey(item.Set, item.CollectorNumber)] = item.Rarity
+	}
+	return m.storeCards(to, copies, rarities, repository.LedgerReasonTrade, round, actor)
+}
+
+// getPendingTrade returns the trade with the given ID, if it is still pending.
+func (m *Manager) getPendingTrade(tradeID int) (repository.Trade, error) {
+	trade, err := m.dataStore.GetTrade(tradeID)
+	if err != nil {
+		return repository.Trade{}, err
+	}
+
+	if trade.Status != repository.TradeStatusPending {
+		return repository.Trade{}, ErrTradeNotPending
+	}
+
+	return trade, nil
+}
+
+// checkTradeLimit returns ErrTradeLimitReached, if the player has already accepted the maximum number of trades in the given round.
+func (m *Manager) checkTradeLimit(userID string, round int) error {
+	if m.config.MaxTradesPerRound <= 0 {
+		return nil
+	}
+
+	count, err := m.dataStore.CountTrades(userID, round, repository.TradeStatusAccepted)
+	if err != nil {
+		return err
+	}
+
+	if count >= m.config.MaxTradesPerRound {
+		return ErrTradeLimitReached
+	}
+
+	return nil
+}
+
+// prepareTradeItems validates the given items against the league's trading rules and the giving players' card pools and balances.
+// It returns the items with the names, set codes and rarities of all cards completed.
+func (m *Manager) prepareTradeItems(ctx context.Context, proposerID, recipientID string, items []repository.TradeItem) ([]repository.TradeItem, error) {
+	prepared := make([]repository.TradeItem, 0, len(items))
+	for _, item := range items {
+		if item.Count < 1 || (item.FromPlayer != proposerID && item.FromPlayer != recipientID) {
+			return nil, ErrInvalidTrade
+		}
+
+		if !item.IsCard() {
+			if item.Currency != repository.CurrencyWildPack && slices.Contains(m.config.UntradeableRarities, repository.Rarity(item.Currency)) {
+				return nil, fmt.Errorf("%w: %s", ErrRarityNotTradeable, item.Currency)
+			}
+
+			player, err := m.dataStore.GetPlayer(item.FromPlayer)
+			if err != nil {
+				return nil, err
+			}
+
+			if player.Balance(item.Currency) < item.Count {
+				return nil, insufficientBalanceError(item.Currency)
+			}
+
+			prepared = append(prepared, item)
+			continue
+		}
+
+		sfCard, err := m.scryfallClient.GetCard(ctx, item.Set, item.CollectorNumber)
+		if err != nil {
+			if errors.Is(err, scryfall.ErrCardNotFound) {
+				return nil, ErrCardNotFound
+			}
+			return nil, err
+		}
+
+		rarity := convertRarity(sfCard.Rarity)
+		if slices.Contains(m.config.UntradeableRarities, rarity) {
+			return nil, fmt.Errorf("%w: %s", ErrRarityNotTradeable, rarity)
+		}
+
+		card := convertScryfallCard(sfCard)
+		item.Name = card.Name
+		item.Set = card.Set
+		item.Rarity = rarity
+
+		owned, err := m.countCopies(item.FromPlayer, item.Card())
+		if err != nil {
+			return nil, err
+		}
+
+		if owned < item.Count {
+			return nil, repository.ErrCardNotInPool
+		}
+
+		prepared = append(prepared, item)
+	}
+
+	return prepared, nil
+}
+
+// countCopies returns the number of copies of the given printing in the player's card pool.
+func (m *Manager) countCopies(userID string, card repository.Card) (int, error) {
+	pool, err := m.dataStore.GetCards(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, owned := range pool {
+		if owned.Set == card.Set && owned.CollectorNumber == card.CollectorNumber && owned.Foil == card.Foil {
+			return owned.Count, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// otherParticipant returns the participant of the trade, who isn't the given player.
+func otherParticipant(trade repository.Trade, userID string) string {
+	if trade.Proposer == userID {
+		return trade.Recipient
+	}
+	return trade.Proposer
+}
+
+// insufficientBalanceError returns the error matching a lack of the given currency.
+func insufficientBalanceError(currency repository.Currency) error {
+	if currency == repository.CurrencyWildPack {
+		return ErrInsufficientWildPacks
+	}
+	return ErrInsufficientWildCards
+}
@@ -0,0 +1,111 @@
+package league
+
+import (
+	"progression/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptTrade(t *testing.T) {
+	dataStore := newRoundDataStore(t, repository.Pairing{Player1: "a", Player2: "b"})
+	dataStore.AddCards("b", repository.Card{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 1})
+	tradeID, err := dataStore.CreateTrade(repository.Trade{Proposer: "a", Recipient: "b", Status: repository.TradeStatusPending, Items: []repository.TradeItem{
+		{FromPlayer: "a", Currency: repository.CurrencyWildPack, Count: 1},
+		{FromPlayer: "b", Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 1},
+	}})
+	require.NoError(t, err)
+	manager := NewLeagueManager(dataStore, nil, nil, DefaultConfig())
+
+	trade, err := manager.AcceptTrade("b", tradeID)
+	require.NoError(t, err)
+
+	assert.Equal(t, repository.TradeStatusAccepted, trade.Status)
+	assert.Equal(t, 0, getPlayer(t, dataStore, "a").WildPacks)
+	assert.Equal(t, 1, getPlayer(t, dataStore, "b").WildPacks)
+	cards, err := dataStore.GetCards("a")
+	require.NoError(t, err)
+	assert.Len(t, cards, 1)
+}
+
+func TestAcceptTrade_changed_offer(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []repository.TradeItem
+		expected error
+	}{
+		{name: "balance", items: []repository.TradeItem{{FromPlayer: "a", Currency: repository.CurrencyWildPack, Count: 2}}, expected: ErrInsufficientWildPacks},
+		{name: "card", items: []repository.TradeItem{{FromPlayer: "b", Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 1}}, expected: repository.ErrCardNotInPool},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataStore := newRoundDataStore(t, repository.Pairing{Player1: "a", Player2: "b"})
+			tradeID, err := dataStore.CreateTrade(repository.Trade{Proposer: "a", Recipient: "b", Status: repository.TradeStatusPending, Items: tt.items})
+			require.NoError(t, err)
+			manager := NewLeagueManager(dataStore, nil, nil, DefaultConfig())
+
+			_, err = manager.AcceptTrade("b", tradeID)
+			assert.ErrorIs(t, err, tt.expected)
+
+			trade, err := dataStore.GetTrade(tradeID)
+			require.NoError(t, err)
+			assert.Equal(t, repository.TradeStatusPending, trade.Status)
+			assert.Equal(t, 1, getPlayer(t, dataStore, "a").WildPacks)
+		})
+	}
+}
+
+func TestAcceptTrade_limit(t *testing.T) {
+	dataStore := newRoundDataStore(t, repository.Pairing{Player1: "a", Player2: "b"})
+	require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: "a", WildPacks: 2}))
+	var tradeIDs []int
+	for range 2 {
+		tradeID, err := dataStore.CreateTrade(repository.Trade{Proposer: "a", Recipient: "b", Status: repository.TradeStatusPending, Items: []repository.TradeItem{
+			{FromPlayer: "a", Currency: repository.CurrencyWildPack, Count: 1},
+		}})
+		require.NoError(t, err)
+		tradeIDs = append(tradeIDs, tradeID)
+	}
+	config := DefaultConfig()
+	config.MaxTradesPerRound = 1
+	manager := NewLeagueManager(dataStore, nil, nil, config)
+
+	_, err := manager.AcceptTrade("b", tradeIDs[0])
+	require.NoError(t, err)
+	_, err = manager.AcceptTrade("b", tradeIDs[1])
+	assert.ErrorIs(t, err, ErrTradeLimitReached)
+
+	trade, err := dataStore.GetTrade(tradeIDs[1])
+	require.NoError(t, err)
+	assert.Equal(t, repository.TradeStatusPending, trade.Status)
+	assert.Equal(t, 1, getPlayer(t, dataStore, "a").WildPacks)
+	assert.Equal(t, 1, getPlayer(t, dataStore, "b").WildPacks)
+}
+
+func TestAcceptTrade_vault(t *testing.T) {
+	dataStore := newRoundDataStore(t, repository.Pairing{Player1: "a", Player2: "b"})
+	card := repository.Card{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 1}
+	dataStore.AddCards("a", card)
+	dataStore.AddCards("b", repository.Card{Name: card.Name, Set: card.Set, CollectorNumber: card.CollectorNumber, Count: maxCopies})
+	tradeID, err := dataStore.CreateTrade(repository.Trade{Proposer: "a", Recipient: "b", Status: repository.TradeStatusPending, Items: []repository.TradeItem{
+		{FromPlayer: "a", Name: card.Name, Set: card.Set, CollectorNumber: card.CollectorNumber, Rarity: repository.RarityRare, Count: 1},
+	}})
+	require.NoError(t, err)
+	config := DefaultConfig()
+	config.VaultEnabled = true
+	manager := NewLeagueManager(dataStore, nil, nil, config)
+
+	_, err = manager.AcceptTrade("b", tradeID)
+	require.NoError(t, err)
+
+	cards, err := dataStore.GetCards("b")
+	require.NoError(t, err)
+	require.Len(t, cards, 1)
+	assert.Equal(t, maxCopies, cards[0].Count, "expected the fifth copy not to be added")
+	assert.Equal(t, config.VaultProgressPerCopy[repository.RarityRare], getPlayer(t, dataStore, "b").VaultProgress)
+	cards, err = dataStore.GetCards("a")
+	require.NoError(t, err)
+	assert.Empty(t, cards)
+}
@@ -0,0 +1,124 @@
+package league
+
+import "progression/repository"
+
+// maxCopies is the number of copies of a card a player can use in a deck. If the vault is enabled, further copies are converted into vault progress.
+const maxCopies = 4
+
+// basicLandNames contains the names of all basic lands, which are never converted into vault progress.
+var basicLandNames = map[string]bool{
+	"Plains":                true,
+	"Island":                true,
+	"Swamp":                 true,
+	"Mountain":              true,
+	"Forest":                true,
+	"Wastes":                true,
+	"Snow-Covered Plains":   true,
+	"Snow-Covered Island":   true,
+	"Snow-Covered Swamp":    true,
+	"Snow-Covered Mountain": true,
+	"Snow-Covered Forest":   true,
+	"Snow-Covered Wastes":   true,
+}
+
+// storeCards adds the given cards to the player's card pool and records them in their pool ledger with the given reason.
+// If the vault is enabled, copies exceeding maxCopies are converted into vault progress instead, which is added to the given player.
+// The rarities of the cards, keyed by printingKey, have to be looked up beforehand, so no slow requests are made while a transaction is open.
+// The caller is responsible for updating the player and storing the returned ledger entries afterward.
+func (m *Manager) storeCards(player *repository.Player, cards []repository.Card, rarities map[string]repository.Rarity, reason repository.LedgerReason, round int, actor string) ([]repository.LedgerEntry, error) {
+	var entries []repository.LedgerEntry
+	if m.config.VaultEnabled {
+		pool, err := m.dataStore.GetCards(player.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		var excess []repository.Card
+		cards, excess = splitExcessCopies(pool, cards)
+
+		entries = m.addVaultProgress(player, excess, rarities, round)
+	}
+
+	err := m.addCards(player.Id, cards, reason, round, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// splitExcessCopies separates the given new cards, each being a single copy, into those which can be added to the given pool and those exceeding maxCopies.
+// Copies are counted by name across all printings. Basic lands never exceed maxCopies.
+func splitExcessCopies(pool []repository.Card, cards []repository.Card) ([]repository.Card, []repository.Card) {
+	copies := make(map[string]int)
+	for _, card := range pool {
+		copies[card.Name] += card.Count
+	}
+
+	kept := make([]repository.Card, 0, len(cards))
+	var excess []repository.Card
+	for _, card := range cards {
+		if !basicLandNames[card.Name] && copies[card.Name] >= maxCopies {
+			excess = append(excess, card)
+			continue
+		}
+
+		copies[card.Name]++
+		kept = append(kept, card)
+	}
+
+	return kept, excess
+}
+
+// addVaultProgress converts the given cards into vault progress according to their rarity, keyed by printingKey.
+// Cards, whose rarity is unknown, are treated as commons, like in opened packs.
+// Whenever the progress reaches the vault size, the vault is opened and its wild cards are awarded to the player.
+func (m *Manager) addVaultProgress(player *repository.Player, cards []repository.Card, rarities map[string]repository.Rarity, round int) []repository.LedgerEntry {
+	progress := 0
+	for _, card := range cards {
+		rarity, found := rarities[printingKey(card.Set, card.CollectorNumber)]
+		if !found {
+			rarity = repository.RarityCommon
+		}
+
+		progress += m.config.VaultProgressPerCopy[rarity]
+	}
+
+	var entries []repository.LedgerEntry
+	if progress != 0 {
+		entries = append(entries, changeBalance(player, repository.CurrencyVaultProgress, progress,
+			repository.LedgerReasonVaultProgress, round, systemActor))
+	}
+
+	if m.config.VaultSize <= 0 {
+		return entries
+	}
+
+	for player.VaultProgress >= m.config.VaultSize {
+		entries = append(entries, changeBalance(player, repository.CurrencyVaultProgress, -m.config.VaultSize,
+			repository.LedgerReasonVaultReward, round, systemActor))
+		for rarity, count := range m.config.VaultRewardWildCards {
+			if count != 0 {
+				entries = append(entries, changeBalance(player, repository.WildCardCurrency(rarity), count,
+					repository.LedgerReasonVaultReward, round, systemActor))
+			}
+		}
+	}
+
+	return entries
+}
+
+// ownsMaxCopies returns whether the player already owns maxCopies copies of the card with the given name.
+func (m *Manager) ownsMaxCopies(userID, cardName string) (bool, error) {
+	if basicLandNames[cardName] {
+		return false, nil
+	}
+
+	pool, err := m.dataStore.GetCards(userID)
+	if err != nil {
+		return false, err
+	}
+
+	_, excess := splitExcessCopies(pool, []repository.Card{{Name: cardName, Count: 1}})
+	return len(excess) > 0, nil
+}
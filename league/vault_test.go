@@ -0,0 +1,67 @@
+package league
+
+import (
+	"progression/repository"
+	"progression/repository/repositorytest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitExcessCopies(t *testing.T) {
+	pool := []repository.Card{
+		{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 3},
+		{Name: "Farfinder", Set: "IKO", CollectorNumber: "2", Count: 4},
+		{Name: "Plains", Set: "IKO", CollectorNumber: "260", Count: 4},
+	}
+	cards := []repository.Card{
+		{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 1},
+		{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Foil: true, Count: 1},
+		{Name: "Farfinder", Set: "IKO", CollectorNumber: "2", Count: 1},
+		{Name: "Plains", Set: "IKO", CollectorNumber: "260", Count: 1},
+		{Name: "Huntmaster Liger", Set: "IKO", CollectorNumber: "16", Count: 1},
+	}
+
+	kept, excess := splitExcessCopies(pool, cards)
+	assert.Equal(t, []repository.Card{cards[0], cards[3], cards[4]}, kept, "kept cards did not match")
+	assert.Equal(t, []repository.Card{cards[1], cards[2]}, excess, "excess cards did not match")
+}
+
+func TestSplitExcessCopies_empty_pool(t *testing.T) {
+	cards := make([]repository.Card, 6)
+	for i := range cards {
+		cards[i] = repository.Card{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 1}
+	}
+
+	kept, excess := splitExcessCopies(nil, cards)
+	assert.Len(t, kept, maxCopies, "expected a playset to be kept")
+	assert.Len(t, excess, 2, "expected the remaining copies to be excess")
+}
+
+func TestStoreCards_vault(t *testing.T) {
+	dataStore := repositorytest.NewMemoryDataStore()
+	dataStore.AddCards("a", repository.Card{Name: "Farfinder", Set: "IKO", CollectorNumber: "2", Count: 4})
+	config := DefaultConfig()
+	config.VaultEnabled = true
+	// without a scryfall client, the rarities have to be the given ones
+	manager := NewLeagueManager(dataStore, nil, nil, config)
+
+	player := repository.Player{Id: "a"}
+	cards := []repository.Card{
+		{Name: "Farfinder", Set: "IKO", CollectorNumber: "2", Count: 1},
+		{Name: "Huntmaster Liger", Set: "IKO", CollectorNumber: "16", Count: 1},
+	}
+	rarities := map[string]repository.Rarity{printingKey("IKO", "2"): repository.RarityRare}
+
+	entries, err := manager.storeCards(&player, cards, rarities, repository.LedgerReasonRedemption, 1, "a")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, repository.CurrencyVaultProgress, entries[0].Currency)
+	assert.Equal(t, config.VaultProgressPerCopy[repository.RarityRare], entries[0].Amount)
+	assert.Equal(t, config.VaultProgressPerCopy[repository.RarityRare], player.VaultProgress)
+
+	pool, err := dataStore.GetCards("a")
+	require.NoError(t, err)
+	assert.Len(t, pool, 2, "expected only the new card to be added")
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
 type Client struct {
@@ -42,11 +43,11 @@ func (c *Client) GetPacks(setCode string, count int) ([]Card, error) {
 	return cards, nil
 }
 
-func (c *Client) CheckCard(setCode string, collectorNumber int) (bool, error) {
+func (c *Client) CheckCard(setCode string, collectorNumber string) (bool, error) {
 	const errMsg = "unable to get card from generator: %w"
 
-	url := fmt.Sprintf("%s/card/%s/%d", c.hostAddress, setCode, collectorNumber)
-	response, err := http.Get(url)
+	cardURL := fmt.Sprintf("%s/card/%s/%s", c.hostAddress, setCode, url.PathEscape(collectorNumber))
+	response, err := http.Get(cardURL)
 	if err != nil {
 		return false, fmt.Errorf(errMsg, err)
 	}
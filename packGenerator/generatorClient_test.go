@@ -11,17 +11,17 @@ import (
 var client = New("http://localhost:8080")
 
 func TestClient_CheckCard_exists(t *testing.T) {
-	exists, err := client.CheckCard("IKO", 1)
+	exists, err := client.CheckCard("IKO", "1")
 	assert.NoError(t, err)
 	assert.True(t, exists)
 }
 
 func TestClient_CheckCard_does_not_exists(t *testing.T) {
-	exists, err := client.CheckCard("IKO", 0)
+	exists, err := client.CheckCard("IKO", "0")
 	assert.NoError(t, err)
 	assert.False(t, exists)
 
-	exists, err = client.CheckCard("abcd", 1)
+	exists, err = client.CheckCard("abcd", "1")
 	assert.NoError(t, err)
 	assert.False(t, exists)
 }
@@ -7,4 +7,5 @@ type Card struct {
 	Set             string `json:"set"`
 	CollectorNumber string `json:"collectorNumber"`
 	ImageURL        string `json:"imageURL"`
+	Rarity          string `json:"rarity"`
 }
@@ -1,25 +1,87 @@
 package repository
 
+import "time"
+
 // DataStore is a backend for persisting the cards generated for every player.
 type DataStore interface {
 	// Connect connects the datastore to its respective backend. This doesn't necessarily entail any actions, but has to be called before the datastore can be used.
 	Connect() error
+	// WithTx runs the given function in a transaction. All operations on the datastore passed to the function are committed, if the function returns nil, and rolled back otherwise.
+	WithTx(f func(tx DataStore) error) error
 	StartLeague() error
 	EndLeague() error
+	// NextRound advances the active league to its next round and returns the new round.
+	NextRound() (int, error)
+	// PreviousRound returns the active league to its previous round and returns that round.
+	PreviousRound() (int, error)
 	GetRound() (int, error)
+	// GetLeague returns the active league.
+	GetLeague() (League, error)
+	// GetLeagues returns every league including the active one, oldest first.
+	GetLeagues() ([]League, error)
+	// StoreLeague stores the league as is, e.g. when restoring a snapshot.
+	StoreLeague(league League) error
+	// UpdateLeague stores the round duration, the deadline and the time of the last reminder of the active league.
+	UpdateLeague(league League) error
 	GetCards(userID string) ([]Card, error)
 	StoreCards(userID string, cards []Card) error
+	// RemoveCards removes the given number of copies of the card from the player's card pool. It fails without removing any copies, if the player doesn't own enough copies.
+	RemoveCards(userID string, card Card, count int) error
+	// GetAllPlayers returns every player, who hasn't dropped from the league.
 	GetAllPlayers() ([]Player, error)
+	GetDroppedPlayers() ([]Player, error)
 	GetPlayer(userID string) (Player, error)
+	// GetPlayerForUpdate returns the player like GetPlayer and locks them until the end of the transaction,
+	// so their balance can be changed based on it without losing concurrent changes.
+	GetPlayerForUpdate(userID string) (Player, error)
 	UpdatePlayer(player Player) error
 	DropPlayer(userID string) error
+	// GetPairing returns the player's pairing of the current round.
 	GetPairing(userID string) (Pairing, error)
+	// GetPairings returns the pairings of the given round of the active league.
+	GetPairings(round int) ([]Pairing, error)
+	// GetAllPairings returns the pairings of every round of every league.
+	GetAllPairings() ([]Pairing, error)
 	StorePairings(pairings []Pairing) error
 	UpdatePairing(pairing Pairing) error
+	// DeletePairings removes every pairing of the given round of the active league.
+	DeletePairings(round int) error
+	// FlagPairing flags the pairing for the admins, but only if it hasn't been reported yet.
+	FlagPairing(pairing Pairing) error
 	IsAdmin(userID string) (bool, error)
 	MakeAdmin(userID string) error
+	GetAdmins() ([]string, error)
 	GetBannedCards() ([]Ban, error)
 	BanCard(cardName string) error
 	UnbanCard(cardName string) error
+	// GetSets returns the unlocked sets in the order they have been unlocked.
 	GetSets() ([]Set, error)
+	// UnlockSet unlocks the set as part of starting the given round.
+	UnlockSet(setCode string, round int) error
+	// LockSet removes the set from the unlocked sets.
+	LockSet(setCode string) error
+	// StoreSets stores the sets as is, including when they have been unlocked, e.g. when restoring a snapshot.
+	StoreSets(sets []Set) error
+	AppendLedgerEntries(entries []LedgerEntry) error
+	GetLedgerEntries(userID string) ([]LedgerEntry, error)
+	// GetRoundLedgerEntries returns the ledger entries of every player recorded during the given round of the active league, oldest first.
+	GetRoundLedgerEntries(round int) ([]LedgerEntry, error)
+	AppendPoolEntries(entries []PoolEntry) error
+	// GetPoolEntries returns every change to the player's card pool, oldest first.
+	GetPoolEntries(userID string) ([]PoolEntry, error)
+	// GetRoundPoolEntries returns the changes to the card pools of every player recorded during the given round of the active league, oldest first.
+	GetRoundPoolEntries(round int) ([]PoolEntry, error)
+	// CreateTrade stores the trade including its items and returns its ID.
+	CreateTrade(trade Trade) (int, error)
+	GetTrade(tradeID int) (Trade, error)
+	// UpdateTradeStatus changes the status of the trade, but only if it currently has the expected status.
+	UpdateTradeStatus(tradeID int, expected, status TradeStatus) error
+	// CountTrades returns the number of trades with the given status the player took part in during the given round.
+	CountTrades(userID string, round int, status TradeStatus) (int, error)
+	// CreateWebhookDeliveries stores the deliveries in the delivery log.
+	CreateWebhookDeliveries(deliveries []WebhookDelivery) error
+	// GetDueWebhookDeliveries returns up to limit pending deliveries, whose next attempt is due at the given time, oldest first.
+	GetDueWebhookDeliveries(now time.Time, limit int) ([]WebhookDelivery, error)
+	// UpdateWebhookDelivery stores the outcome of an attempt to deliver a webhook.
+	UpdateWebhookDelivery(delivery WebhookDelivery) error
 }
@@ -8,8 +8,17 @@ var ErrPairingNotFound = errors.New("pairing not found")
 // ErrPlayerNotFound is returned when a given userID cannot be matched to a valid player.
 var ErrPlayerNotFound = errors.New("player not found")
 
+// ErrCardNotInPool is returned when a player's card pool doesn't contain enough copies of a given card.
+var ErrCardNotInPool = errors.New("not enough copies of card in pool")
+
+// ErrTradeNotFound is returned when a given trade ID does not match a trade with the expected status.
+var ErrTradeNotFound = errors.New("trade not found")
+
 // ErrNoActiveLeague is returned when no league is currently active.
 var ErrNoActiveLeague = errors.New("no active league")
 
 // ErrLeagueAlreadyOngoing is returned when a league cannot be started because another is already ongoing.
 var ErrLeagueAlreadyOngoing = errors.New("another league is already ongoing")
+
+// ErrNoPreviousRound is returned when the active league cannot return to its previous round, because it is in its first round.
+var ErrNoPreviousRound = errors.New("no previous round")
@@ -1,21 +1,177 @@
 package repository
 
+import "time"
+
+// Rarity is the rarity of a card, which determines the kind of wild card needed to redeem it.
+type Rarity string
+
+const (
+	RarityCommon   Rarity = "common"
+	RarityUncommon Rarity = "uncommon"
+	RarityRare     Rarity = "rare"
+	RarityMythic   Rarity = "mythic"
+)
+
+// Rarities contains all rarities ordered from lowest to highest.
+var Rarities = []Rarity{RarityCommon, RarityUncommon, RarityRare, RarityMythic}
+
 // Player represents a player in the league.
 type Player struct {
-	Id        string
-	WildCards int `gorm:"column:wild_card_count"`
-	WildPacks int `gorm:"column:wild_pack_count"`
-	Dropped   bool
+	Id            string
+	WildCommons   int `gorm:"column:wild_common_count"`
+	WildUncommons int `gorm:"column:wild_uncommon_count"`
+	WildRares     int `gorm:"column:wild_rare_count"`
+	WildMythics   int `gorm:"column:wild_mythic_count"`
+	WildPacks     int `gorm:"column:wild_pack_count"`
+	VaultProgress int
+	Dropped       bool
+}
+
+// WildCards returns the number of wild cards of the given rarity the player can redeem.
+func (p Player) WildCards(rarity Rarity) int {
+	switch rarity {
+	case RarityCommon:
+		return p.WildCommons
+	case RarityUncommon:
+		return p.WildUncommons
+	case RarityRare:
+		return p.WildRares
+	case RarityMythic:
+		return p.WildMythics
+	default:
+		return 0
+	}
+}
+
+// AddWildCards adds the given number of wild cards of the given rarity to the player's balance. A negative count removes wild cards.
+func (p *Player) AddWildCards(rarity Rarity, count int) {
+	switch rarity {
+	case RarityCommon:
+		p.WildCommons += count
+	case RarityUncommon:
+		p.WildUncommons += count
+	case RarityRare:
+		p.WildRares += count
+	case RarityMythic:
+		p.WildMythics += count
+	}
+}
+
+// Currency is a kind of balance a player holds, i.e. wild packs, wild cards of a single rarity or vault progress.
+type Currency string
+
+// CurrencyWildPack is the currency of wild packs. The currencies of wild cards are named after their rarity.
+const CurrencyWildPack Currency = "pack"
+
+// CurrencyVaultProgress is the currency of vault progress.
+const CurrencyVaultProgress Currency = "vault"
+
+// WildCardCurrency returns the currency of wild cards of the given rarity.
+func WildCardCurrency(rarity Rarity) Currency {
+	return Currency(rarity)
+}
+
+// Balance returns the player's balance of the given currency.
+func (p Player) Balance(currency Currency) int {
+	switch currency {
+	case CurrencyWildPack:
+		return p.WildPacks
+	case CurrencyVaultProgress:
+		return p.VaultProgress
+	default:
+		return p.WildCards(Rarity(currency))
+	}
+}
+
+// AddBalance adds the given amount to the player's balance of the given currency. A negative amount is a debit.
+func (p *Player) AddBalance(currency Currency, amount int) {
+	switch currency {
+	case CurrencyWildPack:
+		p.WildPacks += amount
+	case CurrencyVaultProgress:
+		p.VaultProgress += amount
+	default:
+		p.AddWildCards(Rarity(currency), amount)
+	}
+}
+
+// LedgerReason explains why a player's balance or card pool changed.
+type LedgerReason string
+
+const (
+	LedgerReasonRoundReward     LedgerReason = "round_reward"
+	LedgerReasonLossReward      LedgerReason = "loss_reward"
+	LedgerReasonVaultReward     LedgerReason = "vault_reward"
+	LedgerReasonRedemption      LedgerReason = "redemption"
+	LedgerReasonAdminAdjustment LedgerReason = "admin_adjustment"
+	LedgerReasonTrade           LedgerReason = "trade"
+	LedgerReasonRoundPacks      LedgerReason = "round_packs"
+	LedgerReasonStartingPacks   LedgerReason = "starting_packs"
+	LedgerReasonVaultProgress   LedgerReason = "vault_progress"
+	LedgerReasonRollback        LedgerReason = "rollback"
+)
+
+// LedgerEntry represents a single credit or debit of a player's balance. Entries are never changed once recorded.
+// Like pairings, entries are assigned to the active league by their creation time, which defaults to the database's time.
+type LedgerEntry struct {
+	Id        int `gorm:"primaryKey"`
+	PlayerId  string
+	Currency  Currency
+	Amount    int
+	Reason    LedgerReason
+	Round     int
+	Actor     string
+	Note      string
+	CreatedAt time.Time `gorm:"default:now()"`
+}
+
+// PoolEntry represents copies of a card being added to or, with a negative count, removed from a player's card pool.
+// Like ledger entries, pool entries are never changed once recorded.
+type PoolEntry struct {
+	Id              int `gorm:"primaryKey"`
+	PlayerId        string
+	Name            string
+	Set             string `gorm:"column:set_code"`
+	CollectorNumber string
+	Foil            bool
+	ScryfallURI     string
+	ImageURL        string
+	Count           int
+	Reason          LedgerReason
+	Round           int
+	Actor           string
+	CreatedAt       time.Time `gorm:"default:now()"`
 }
 
-// Card represents a card in a players card pool.
+// Card represents a card in a players card pool. Foil and non-foil copies of the same printing are separate cards.
 type Card struct {
 	Name            string
-	Set             string
-	CollectorNumber int
+	Set             string `gorm:"column:set_code"`
+	CollectorNumber string
+	Foil            bool
+	ScryfallURI     string
+	ImageURL        string
 	Count           int
 }
 
+// League represents a league. Only a single league is active at any time.
+type League struct {
+	Round     int
+	Active    bool
+	StartedAt *time.Time
+	// RoundDurationSeconds is the time players have to report their matches once a round has started. Zero means rounds have no time limit.
+	RoundDurationSeconds int
+	// RoundDeadline is when unreported matches of the current round are resolved. It is nil, if the round has no time limit or the deadline has passed.
+	RoundDeadline *time.Time
+	// RemindedAt is when players with unreported matches have last been reminded of the deadline.
+	RemindedAt *time.Time
+}
+
+// RoundDuration returns the time players have to report their matches once a round has started. Zero means rounds have no time limit.
+func (l League) RoundDuration() time.Duration {
+	return time.Duration(l.RoundDurationSeconds) * time.Second
+}
+
 // Pairing represents a pairing of players in a round. Once any scores have been reported, the pairing is assumed to be over.
 type Pairing struct {
 	Round   int    `gorm:"primaryKey"`
@@ -24,6 +180,11 @@ type Pairing struct {
 	Wins1   int
 	Wins2   int
 	Draws   int
+	// Flagged marks a pairing, which hasn't been reported by the round's deadline, for the admins to resolve.
+	Flagged bool
+	// CreatedAt assigns the pairing to the league, which was active when it was created, since the rounds of every league start at 0.
+	// It defaults to the database's time, so it can't precede the start of the league due to clocks drifting apart.
+	CreatedAt time.Time `gorm:"default:now()"`
 }
 
 // Ban represents a banned card.
@@ -33,5 +194,85 @@ type Ban struct {
 
 // Set represents an unlocked set in the league.
 type Set struct {
-	SetCode string `gorm:"primaryKey"`
+	SetCode    string    `gorm:"primaryKey"`
+	UnlockedAt time.Time `gorm:"autoCreateTime"`
+	// Round is the round, which started when the set has been unlocked.
+	Round int
+}
+
+// TradeStatus is the state of a trade offer. Only pending trades can be accepted, declined or countered.
+type TradeStatus string
+
+const (
+	TradeStatusPending   TradeStatus = "pending"
+	TradeStatusAccepted  TradeStatus = "accepted"
+	TradeStatusDeclined  TradeStatus = "declined"
+	TradeStatusCountered TradeStatus = "countered"
+)
+
+// Trade represents an offer of one player to exchange cards, wild cards and packs with another player.
+type Trade struct {
+	Id        int `gorm:"primaryKey"`
+	Round     int
+	Proposer  string
+	Recipient string
+	Status    TradeStatus
+	CreatedAt time.Time
+	Items     []TradeItem `gorm:"-"`
+}
+
+// TradeItem represents something changing hands as part of a trade. It is either wild cards or packs of a currency or copies of a card.
+type TradeItem struct {
+	TradeId    int
+	FromPlayer string
+	// Currency is only set for wild cards and packs.
+	Currency        Currency
+	Name            string
+	Set             string `gorm:"column:set_code"`
+	CollectorNumber string
+	Foil            bool
+	// Rarity is only set for cards. It is looked up when the trade is offered, so accepting it needs no slow requests.
+	Rarity Rarity
+	Count  int
+}
+
+// IsCard returns whether the item is a card instead of wild cards or packs.
+func (t TradeItem) IsCard() bool {
+	return t.Currency == ""
+}
+
+// Card returns a single copy of the card traded in the item.
+func (t TradeItem) Card() Card {
+	return Card{
+		Name:            t.Name,
+		Set:             t.Set,
+		CollectorNumber: t.CollectorNumber,
+		Foil:            t.Foil,
+		Count:           1,
+	}
+}
+
+// WebhookDeliveryStatus is the state of a webhook delivery. Only pending deliveries are attempted.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery represents the delivery of a league event to a webhook URL, including every failed attempt so far.
+type WebhookDelivery struct {
+	Id            int `gorm:"primaryKey"`
+	URL           string
+	Event         string
+	Payload       string
+	Status        WebhookDeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	// ResponseCode is the HTTP status code of the last attempt. It is zero, if no response has been received.
+	ResponseCode int
+	LastError    string
+	CreatedAt    time.Time
+	DeliveredAt  *time.Time
 }
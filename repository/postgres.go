@@ -4,9 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type postgresDataStore struct {
@@ -39,6 +41,14 @@ func (p *postgresDataStore) Connect() error {
 	return nil
 }
 
+func (p *postgresDataStore) WithTx(f func(tx DataStore) error) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		txDataStore := *p
+		txDataStore.db = tx
+		return f(&txDataStore)
+	})
+}
+
 func (p *postgresDataStore) generateDSN() string {
 	// TODO Properly extract the current timezone of the server...
 	TZ := "Europe/Berlin"
@@ -49,10 +59,14 @@ func (p *postgresDataStore) generateDSN() string {
 func (p *postgresDataStore) StoreCards(userID string, cards []Card) error {
 	const errMsg = "failed to store cards: %w"
 	const query = `
-			INSERT INTO player_card_pool (id, name, set_code, collector_number, count) VALUES %s
-			ON CONFLICT (id, set_code, collector_number)
+			INSERT INTO player_card_pool (id, name, set_code, collector_number, foil, scryfall_uri, image_url, count) VALUES %s
+			ON CONFLICT (id, set_code, collector_number, foil)
 			DO UPDATE SET count = EXCLUDED.count + player_card_pool.count`
 
+	if len(cards) == 0 {
+		return nil
+	}
+
 	fields, args := generateRows(userID, cards)
 	result := p.db.Exec(fmt.Sprintf(query, fields), args...)
 
@@ -63,11 +77,37 @@ func (p *postgresDataStore) StoreCards(userID string, cards []Card) error {
 	return nil
 }
 
+func (p *postgresDataStore) RemoveCards(userID string, card Card, count int) error {
+	const errMsg = "failed to remove cards: %w"
+	const updateQuery = `
+			UPDATE player_card_pool SET count = count - ?
+			WHERE id = ? AND set_code = ? AND collector_number = ? AND foil = ? AND count >= ?`
+	const deleteQuery = `
+			DELETE FROM player_card_pool
+			WHERE id = ? AND set_code = ? AND collector_number = ? AND foil = ? AND count = 0`
+
+	result := p.db.Exec(updateQuery, count, userID, card.Set, card.CollectorNumber, card.Foil, count)
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf(errMsg, ErrCardNotInPool)
+	}
+
+	result = p.db.Exec(deleteQuery, userID, card.Set, card.CollectorNumber, card.Foil)
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	return nil
+}
+
 func (p *postgresDataStore) GetSets() ([]Set, error) {
 	const errMsg = "failed to get sets: %w"
 
 	var sets []Set
-	result := p.db.Table("sets").Find(&sets)
+	result := p.db.Table("sets").Order("unlocked_at, set_code").Find(&sets)
 	if result.Error != nil {
 		return nil, fmt.Errorf(errMsg, result.Error)
 	}
@@ -75,6 +115,43 @@ func (p *postgresDataStore) GetSets() ([]Set, error) {
 	return sets, nil
 }
 
+func (p *postgresDataStore) UnlockSet(setCode string, round int) error {
+	const errMsg = "failed to unlock set: %w"
+
+	result := p.db.Table("sets").Create(&Set{SetCode: setCode, Round: round})
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	return nil
+}
+
+func (p *postgresDataStore) LockSet(setCode string) error {
+	const errMsg = "failed to lock set: %w"
+
+	result := p.db.Table("sets").Where("set_code = ?", setCode).Delete(&Set{})
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	return nil
+}
+
+func (p *postgresDataStore) StoreSets(sets []Set) error {
+	const errMsg = "failed to store sets: %w"
+
+	if len(sets) == 0 {
+		return nil
+	}
+
+	result := p.db.Table("sets").Create(&sets)
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	return nil
+}
+
 func (p *postgresDataStore) GetBannedCards() ([]Ban, error) {
 	const errMsg = "failed to get banned cards: %w"
 
@@ -134,7 +211,7 @@ func generateRows(userID string, cards []Card) (string, []any) {
 
 	// group similar cards
 	for _, card := range cards {
-		key := fmt.Sprintf("%s|%d", card.Set, card.CollectorNumber)
+		key := fmt.Sprintf("%s|%s|%t", card.Set, card.CollectorNumber, card.Foil)
 		cardAndCount, exists := cardCounts[key]
 		if !exists {
 			cardAndCount.Card = card
@@ -146,10 +223,11 @@ func generateRows(userID string, cards []Card) (string, []any) {
 
 	// generate row per card
 	inClause := make([]string, 0, len(cardCounts))
-	args := make([]any, 0, len(cardCounts)*4)
+	args := make([]any, 0, len(cardCounts)*8)
 	for _, cardAndCount := range cardCounts {
-		inClause = append(inClause, "(?, ?, ?, ?, ?)")
-		args = append(args, userID, cardAndCount.Name, cardAndCount.Set, cardAndCount.CollectorNumber, cardAndCount.Count)
+		inClause = append(inClause, "(?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, userID, cardAndCount.Name, cardAndCount.Set, cardAndCount.CollectorNumber, cardAndCount.Foil,
+			cardAndCount.ScryfallURI, cardAndCount.ImageURL, cardAndCount.Count)
 	}
 
 	inClauseString := strings.Join(inClause, ", ")
@@ -182,6 +260,18 @@ func (p *postgresDataStore) GetAllPlayers() ([]Player, error) {
 	return players, nil
 }
 
+func (p *postgresDataStore) GetDroppedPlayers() ([]Player, error) {
+	const errMsg = "failed to get dropped players: %w"
+
+	var players []Player
+	result := p.db.Table("player").Where("dropped = true").Scan(&players)
+	if result.Error != nil {
+		return nil, fmt.Errorf(errMsg, result.Error)
+	}
+
+	return players, nil
+}
+
 func (p *postgresDataStore) GetPlayer(userID string) (Player, error) {
 	const errMsg = "failed to get player: %w"
 
@@ -198,6 +288,22 @@ func (p *postgresDataStore) GetPlayer(userID string) (Player, error) {
 	return player, nil
 }
 
+func (p *postgresDataStore) GetPlayerForUpdate(userID string) (Player, error) {
+	const errMsg = "failed to get player for update: %w"
+
+	var player Player
+	result := p.db.Table("player").Clauses(clause.Locking{Strength: "UPDATE"}).First(&player, "id = ?", userID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return Player{}, ErrPlayerNotFound
+		}
+
+		return player, fmt.Errorf(errMsg, result.Error)
+	}
+
+	return player, nil
+}
+
 func (p *postgresDataStore) UpdatePlayer(player Player) error {
 	const errMsg = "failed to update player: %w"
 
@@ -214,8 +320,9 @@ func (p *postgresDataStore) GetPairing(userID string) (Pairing, error) {
 
 	var pairing Pairing
 	result := p.db.Table("pairing").
-		Where("player1 = ?", userID).
-		Or("player2 = ?", userID).
+		Where("round = (SELECT round FROM league WHERE active = true)").
+		Where("created_at >= (SELECT started_at FROM league WHERE active = true)").
+		Where("player1 = ? OR player2 = ?", userID, userID).
 		Find(&pairing)
 	if result.Error != nil {
 		return pairing, fmt.Errorf(errMsg, result.Error)
@@ -228,6 +335,33 @@ func (p *postgresDataStore) GetPairing(userID string) (Pairing, error) {
 	return pairing, nil
 }
 
+func (p *postgresDataStore) GetPairings(round int) ([]Pairing, error) {
+	const errMsg = "failed to get pairings: %w"
+
+	var pairings []Pairing
+	result := p.db.Table("pairing").
+		Where("round = ?", round).
+		Where("created_at >= (SELECT started_at FROM league WHERE active = true)").
+		Find(&pairings)
+	if result.Error != nil {
+		return nil, fmt.Errorf(errMsg, result.Error)
+	}
+
+	return pairings, nil
+}
+
+func (p *postgresDataStore) GetAllPairings() ([]Pairing, error) {
+	const errMsg = "failed to get all pairings: %w"
+
+	var pairings []Pairing
+	result := p.db.Table("pairing").Order("round, player1, player2").Find(&pairings)
+	if result.Error != nil {
+		return nil, fmt.Errorf(errMsg, result.Error)
+	}
+
+	return pairings, nil
+}
+
 func (p *postgresDataStore) StorePairings(pairings []Pairing) error {
 	const errMsg = "failed to store pairings: %w"
 
@@ -244,7 +378,8 @@ func (p *postgresDataStore) UpdatePairing(pairing Pairing) error {
 
 	const query = `UPDATE pairing SET wins1 = ?, wins2 = ?, draws = ?
                WHERE round = ? AND player1 = ? AND player2 = ?
-               AND wins1 = 0 AND wins2 = 0 AND draws = 0`
+               AND wins1 = 0 AND wins2 = 0 AND draws = 0
+               AND created_at >= (SELECT started_at FROM league WHERE active = true)`
 
 	result := p.db.Exec(query, pairing.Wins1, pairing.Wins2, pairing.Draws, pairing.Round, pairing.Player1, pairing.Player2)
 	if result.Error != nil {
@@ -258,9 +393,43 @@ func (p *postgresDataStore) UpdatePairing(pairing Pairing) error {
 	return nil
 }
 
+func (p *postgresDataStore) DeletePairings(round int) error {
+	const errMsg = "failed to delete pairings: %w"
+
+	result := p.db.Table("pairing").
+		Where("round = ?", round).
+		Where("created_at >= (SELECT started_at FROM league WHERE active = true)").
+		Delete(&Pairing{})
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	return nil
+}
+
+func (p *postgresDataStore) FlagPairing(pairing Pairing) error {
+	const errMsg = "failed to flag pairing: %w"
+
+	const query = `UPDATE pairing SET flagged = true
+               WHERE round = ? AND player1 = ? AND player2 = ?
+               AND wins1 = 0 AND wins2 = 0 AND draws = 0
+               AND created_at >= (SELECT started_at FROM league WHERE active = true)`
+
+	result := p.db.Exec(query, pairing.Round, pairing.Player1, pairing.Player2)
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf(errMsg, ErrPairingNotFound)
+	}
+
+	return nil
+}
+
 func (p *postgresDataStore) StartLeague() error {
 	const errMsg = "failed to start league: %w"
-	const query = `INSERT INTO league VALUES (0, true, now());`
+	const query = `INSERT INTO league (round, active, started_at) VALUES (0, true, now());`
 
 	_, err := p.GetRound()
 	if err != nil && !errors.Is(err, ErrNoActiveLeague) {
@@ -300,6 +469,45 @@ func (p *postgresDataStore) EndLeague() error {
 	return nil
 }
 
+func (p *postgresDataStore) NextRound() (int, error) {
+	const errMsg = "failed to advance round: %w"
+	const query = `UPDATE league SET round = round + 1 WHERE active = true RETURNING round;`
+
+	var round int
+	result := p.db.Raw(query).Scan(&round)
+	if result.Error != nil {
+		return 0, fmt.Errorf(errMsg, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return 0, fmt.Errorf(errMsg, ErrNoActiveLeague)
+	}
+
+	return round, nil
+}
+
+func (p *postgresDataStore) PreviousRound() (int, error) {
+	const errMsg = "failed to return to previous round: %w"
+	const query = `UPDATE league SET round = round - 1 WHERE active = true AND round > 0 RETURNING round;`
+
+	_, err := p.GetRound()
+	if err != nil {
+		return 0, fmt.Errorf(errMsg, err)
+	}
+
+	var round int
+	result := p.db.Raw(query).Scan(&round)
+	if result.Error != nil {
+		return 0, fmt.Errorf(errMsg, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return 0, fmt.Errorf(errMsg, ErrNoPreviousRound)
+	}
+
+	return round, nil
+}
+
 func (p *postgresDataStore) GetRound() (int, error) {
 	const errMsg = "failed to get current round: %w"
 	const query = `SELECT round FROM league where active = true;`
@@ -317,6 +525,61 @@ func (p *postgresDataStore) GetRound() (int, error) {
 	return round, nil
 }
 
+func (p *postgresDataStore) GetLeague() (League, error) {
+	const errMsg = "failed to get league: %w"
+
+	var league League
+	result := p.db.Table("league").Where("active = true").Find(&league)
+	if result.Error != nil {
+		return League{}, fmt.Errorf(errMsg, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return League{}, fmt.Errorf(errMsg, ErrNoActiveLeague)
+	}
+
+	return league, nil
+}
+
+func (p *postgresDataStore) GetLeagues() ([]League, error) {
+	const errMsg = "failed to get leagues: %w"
+
+	var leagues []League
+	result := p.db.Table("league").Order("started_at NULLS FIRST").Find(&leagues)
+	if result.Error != nil {
+		return nil, fmt.Errorf(errMsg, result.Error)
+	}
+
+	return leagues, nil
+}
+
+func (p *postgresDataStore) StoreLeague(league League) error {
+	const errMsg = "failed to store league: %w"
+
+	result := p.db.Table("league").Create(&league)
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	return nil
+}
+
+func (p *postgresDataStore) UpdateLeague(league League) error {
+	const errMsg = "failed to update league: %w"
+	const query = `UPDATE league SET round_duration_seconds = ?, round_deadline = ?, reminded_at = ? WHERE active = true;`
+
+	result := p.db.Exec(query, league.RoundDurationSeconds, league.RoundDeadline, league.RemindedAt)
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf(errMsg, ErrNoActiveLeague)
+	}
+
+	return nil
+}
+
 func (p *postgresDataStore) IsAdmin(userID string) (bool, error) {
 	const errMsg = "failed to check admin permission: %w"
 
@@ -340,3 +603,236 @@ func (p *postgresDataStore) MakeAdmin(userID string) error {
 
 	return nil
 }
+
+func (p *postgresDataStore) GetAdmins() ([]string, error) {
+	const errMsg = "failed to get admins: %w"
+
+	var adminIDs []string
+	result := p.db.Table("admin").Order("id").Pluck("id", &adminIDs)
+	if result.Error != nil {
+		return nil, fmt.Errorf(errMsg, result.Error)
+	}
+
+	return adminIDs, nil
+}
+
+func (p *postgresDataStore) AppendLedgerEntries(entries []LedgerEntry) error {
+	const errMsg = "failed to append ledger entries: %w"
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	result := p.db.Table("ledger").Create(&entries)
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	return nil
+}
+
+func (p *postgresDataStore) GetLedgerEntries(userID string) ([]LedgerEntry, error) {
+	const errMsg = "failed to get ledger entries: %w"
+
+	var entries []LedgerEntry
+	result := p.db.Table("ledger").
+		Where("player_id = ?", userID).
+		Order("id").
+		Find(&entries)
+	if result.Error != nil {
+		return nil, fmt.Errorf(errMsg, result.Error)
+	}
+
+	return entries, nil
+}
+
+func (p *postgresDataStore) CreateTrade(trade Trade) (int, error) {
+	const errMsg = "failed to create trade: %w"
+
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("trade").Create(&trade)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if len(trade.Items) == 0 {
+			return nil
+		}
+
+		for i := range trade.Items {
+			trade.Items[i].TradeId = trade.Id
+		}
+
+		return tx.Table("trade_item").Create(&trade.Items).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf(errMsg, err)
+	}
+
+	return trade.Id, nil
+}
+
+func (p *postgresDataStore) GetTrade(tradeID int) (Trade, error) {
+	const errMsg = "failed to get trade: %w"
+
+	var trade Trade
+	result := p.db.Table("trade").First(&trade, "id = ?", tradeID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return Trade{}, ErrTradeNotFound
+		}
+
+		return Trade{}, fmt.Errorf(errMsg, result.Error)
+	}
+
+	result = p.db.Table("trade_item").Where("trade_id = ?", tradeID).Find(&trade.Items)
+	if result.Error != nil {
+		return Trade{}, fmt.Errorf(errMsg, result.Error)
+	}
+
+	return trade, nil
+}
+
+func (p *postgresDataStore) UpdateTradeStatus(tradeID int, expected, status TradeStatus) error {
+	const errMsg = "failed to update trade status: %w"
+
+	result := p.db.Table("trade").
+		Where("id = ? AND status = ?", tradeID, expected).
+		Update("status", status)
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf(errMsg, ErrTradeNotFound)
+	}
+
+	return nil
+}
+
+func (p *postgresDataStore) CountTrades(userID string, round int, status TradeStatus) (int, error) {
+	const errMsg = "failed to count trades: %w"
+
+	var count int64
+	result := p.db.Table("trade").
+		Where("round = ? AND status = ? AND (proposer = ? OR recipient = ?)", round, status, userID, userID).
+		Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf(errMsg, result.Error)
+	}
+
+	return int(count), nil
+}
+
+func (p *postgresDataStore) CreateWebhookDeliveries(deliveries []WebhookDelivery) error {
+	const errMsg = "failed to create webhook deliveries: %w"
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	result := p.db.Table("webhook_delivery").Create(&deliveries)
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	return nil
+}
+
+func (p *postgresDataStore) GetDueWebhookDeliveries(now time.Time, limit int) ([]WebhookDelivery, error) {
+	const errMsg = "failed to get due webhook deliveries: %w"
+
+	var deliveries []WebhookDelivery
+	result := p.db.Table("webhook_delivery").
+		Where("status = ? AND next_attempt_at <= ?", WebhookDeliveryPending, now).
+		Order("id").
+		Limit(limit).
+		Find(&deliveries)
+	if result.Error != nil {
+		return nil, fmt.Errorf(errMsg, result.Error)
+	}
+
+	return deliveries, nil
+}
+
+func (p *postgresDataStore) UpdateWebhookDelivery(delivery WebhookDelivery) error {
+	const errMsg = "failed to update webhook delivery: %w"
+
+	result := p.db.Table("webhook_delivery").
+		Where("id = ?", delivery.Id).
+		Updates(map[string]any{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"response_code":   delivery.ResponseCode,
+			"last_error":      delivery.LastError,
+			"delivered_at":    delivery.DeliveredAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	return nil
+}
+
+func (p *postgresDataStore) GetRoundLedgerEntries(round int) ([]LedgerEntry, error) {
+	const errMsg = "failed to get ledger entries of round: %w"
+
+	var entries []LedgerEntry
+	result := p.db.Table("ledger").
+		Where("round = ?", round).
+		Where("created_at >= (SELECT started_at FROM league WHERE active = true)").
+		Order("id").
+		Find(&entries)
+	if result.Error != nil {
+		return nil, fmt.Errorf(errMsg, result.Error)
+	}
+
+	return entries, nil
+}
+
+func (p *postgresDataStore) AppendPoolEntries(entries []PoolEntry) error {
+	const errMsg = "failed to append pool entries: %w"
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	result := p.db.Table("pool_ledger").Create(&entries)
+	if result.Error != nil {
+		return fmt.Errorf(errMsg, result.Error)
+	}
+
+	return nil
+}
+
+func (p *postgresDataStore) GetPoolEntries(userID string) ([]PoolEntry, error) {
+	const errMsg = "failed to get pool entries: %w"
+
+	var entries []PoolEntry
+	result := p.db.Table("pool_ledger").
+		Where("player_id = ?", userID).
+		Order("id").
+		Find(&entries)
+	if result.Error != nil {
+		return nil, fmt.Errorf(errMsg, result.Error)
+	}
+
+	return entries, nil
+}
+
+func (p *postgresDataStore) GetRoundPoolEntries(round int) ([]PoolEntry, error) {
+	const errMsg = "failed to get pool entries of round: %w"
+
+	var entries []PoolEntry
+	result := p.db.Table("pool_ledger").
+		Where("round = ?", round).
+		Where("created_at >= (SELECT started_at FROM league WHERE active = true)").
+		Order("id").
+		Find(&entries)
+	if result.Error != nil {
+		return nil, fmt.Errorf(errMsg, result.Error)
+	}
+
+	return entries, nil
+}
@@ -42,11 +42,41 @@ func setupTest() {
 	if err := pgDS.db.Exec("TRUNCATE TABLE pairing;").Error; err != nil {
 		log.Fatal(err)
 	}
+	if err := pgDS.db.Exec("TRUNCATE TABLE ledger;").Error; err != nil {
+		log.Fatal(err)
+	}
+	if err := pgDS.db.Exec("TRUNCATE TABLE webhook_delivery;").Error; err != nil {
+		log.Fatal(err)
+	}
+	if err := pgDS.db.Exec("TRUNCATE TABLE sets;").Error; err != nil {
+		log.Fatal(err)
+	}
+	if err := pgDS.db.Exec("TRUNCATE TABLE pool_ledger;").Error; err != nil {
+		log.Fatal(err)
+	}
 }
 
 func teardownTest() {
 }
 
+// startLeague starts a league and advances it to the given round.
+func startLeague(t *testing.T, round int) {
+	t.Helper()
+
+	err := dataStore.StartLeague()
+	assert.NoError(t, err, "failed to start league")
+	for range round {
+		_, err = dataStore.NextRound()
+		assert.NoError(t, err, "failed to advance round")
+	}
+}
+
+// withoutCreatedAt returns the pairing without its creation time, which is set by the datastore.
+func withoutCreatedAt(pairing Pairing) Pairing {
+	pairing.CreatedAt = time.Time{}
+	return pairing
+}
+
 func TestMain(m *testing.M) {
 	setupSuite()
 	code := m.Run()
@@ -61,7 +91,7 @@ func TestInsertCardPool(t *testing.T) {
 		{
 			Name:            "Adaptive Shimmerer",
 			Set:             "IKO",
-			CollectorNumber: 1,
+			CollectorNumber: "1",
 		},
 	}
 	playerID := "test_player" + strconv.FormatInt(time.Now().UnixMilli(), 10)
@@ -78,17 +108,17 @@ func TestGetCardPool(t *testing.T) {
 		{
 			Name:            "Adaptive Shimmerer",
 			Set:             "IKO",
-			CollectorNumber: 1,
+			CollectorNumber: "1",
 		},
 		{
 			Name:            "Farfinder",
 			Set:             "IKO",
-			CollectorNumber: 2,
+			CollectorNumber: "2",
 		},
 		{
 			Name:            "Adaptive Shimmerer",
 			Set:             "IKO",
-			CollectorNumber: 1,
+			CollectorNumber: "1",
 		},
 	}
 	playerID := "test_player" + strconv.FormatInt(time.Now().UnixMilli(), 10)
@@ -110,12 +140,12 @@ func TestCardPoolDeduplicate(t *testing.T) {
 		{
 			Name:            "Adaptive Shimmerer",
 			Set:             "IKO",
-			CollectorNumber: 1,
+			CollectorNumber: "1",
 		},
 		{
 			Name:            "Adaptive Shimmerer",
 			Set:             "IKO",
-			CollectorNumber: 1,
+			CollectorNumber: "1",
 		},
 	}
 	playerID := "test_player" + strconv.FormatInt(time.Now().UnixMilli(), 10)
@@ -131,13 +161,46 @@ func TestCardPoolDeduplicate(t *testing.T) {
 	teardownTest()
 }
 
+func TestCardPoolFoilsSeparate(t *testing.T) {
+	setupTest()
+
+	cards := []Card{
+		{
+			Name:            "Adaptive Shimmerer",
+			Set:             "IKO",
+			CollectorNumber: "1",
+			ScryfallURI:     "https://scryfall.com/card/iko/1/adaptive-shimmerer",
+		},
+		{
+			Name:            "Adaptive Shimmerer",
+			Set:             "IKO",
+			CollectorNumber: "1",
+			Foil:            true,
+			ScryfallURI:     "https://scryfall.com/card/iko/1/adaptive-shimmerer",
+		},
+	}
+	playerID := "test_player" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	err := dataStore.StoreCards(playerID, cards)
+	assert.NoError(t, err, "failed to store cards")
+
+	storedCards, err := dataStore.GetCards(playerID)
+	assert.NoError(t, err, "failed to get cards")
+
+	assert.Len(t, storedCards, 2, "expected foil and non-foil to be stored separately")
+	for _, card := range storedCards {
+		assert.Equal(t, "IKO", card.Set, "set did not match")
+		assert.Equal(t, cards[0].ScryfallURI, card.ScryfallURI, "scryfall uri did not match")
+	}
+	teardownTest()
+}
+
 func TestInsertPlayer(t *testing.T) {
 	setupTest()
 
 	playerID := "test_player" + strconv.FormatInt(time.Now().UnixMilli(), 10)
 	player := Player{
 		Id:        playerID,
-		WildCards: 0,
 		WildPacks: 0,
 	}
 
@@ -152,7 +215,7 @@ func TestGetPlayer(t *testing.T) {
 	playerID := "test_player" + strconv.FormatInt(time.Now().UnixMilli(), 10)
 	player := Player{
 		Id:        playerID,
-		WildCards: 1,
+		WildRares: 1,
 		WildPacks: 23,
 	}
 
@@ -165,20 +228,61 @@ func TestGetPlayer(t *testing.T) {
 	teardownTest()
 }
 
+func TestGetPlayerForUpdate(t *testing.T) {
+	setupTest()
+
+	playerID := "test_player" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	player := Player{Id: playerID, WildPacks: 3}
+	err := dataStore.UpdatePlayer(player)
+	assert.NoError(t, err, "failed to store player")
+
+	err = dataStore.WithTx(func(tx DataStore) error {
+		storedPlayer, err := tx.GetPlayerForUpdate(playerID)
+		assert.NoError(t, err, "failed to get player for update")
+		assert.Equal(t, player, storedPlayer, "player did not match")
+
+		_, err = tx.GetPlayerForUpdate("unknown")
+		assert.ErrorIs(t, err, ErrPlayerNotFound, "expected unknown player to be not found")
+		return nil
+	})
+	assert.NoError(t, err, "failed to run transaction")
+	teardownTest()
+}
+
+func TestGetDroppedPlayers(t *testing.T) {
+	setupTest()
+
+	playerID := "test_player" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	err := dataStore.UpdatePlayer(Player{Id: playerID})
+	assert.NoError(t, err, "failed to store player")
+
+	players, err := dataStore.GetDroppedPlayers()
+	assert.NoError(t, err, "failed to get dropped players")
+	assert.Empty(t, players, "expected no dropped players")
+
+	err = dataStore.DropPlayer(playerID)
+	assert.NoError(t, err, "failed to drop player")
+
+	players, err = dataStore.GetDroppedPlayers()
+	assert.NoError(t, err, "failed to get dropped players")
+	assert.Equal(t, []Player{{Id: playerID, Dropped: true}}, players, "dropped players did not match")
+	teardownTest()
+}
+
 func TestUpdatePlayer(t *testing.T) {
 	setupTest()
 
 	playerID := "test_player" + strconv.FormatInt(time.Now().UnixMilli(), 10)
 	player := Player{
 		Id:        playerID,
-		WildCards: 1,
+		WildRares: 1,
 		WildPacks: 23,
 	}
 
 	err := dataStore.UpdatePlayer(player)
 	assert.NoError(t, err, "failed to store player")
 
-	player.WildCards = 0
+	player.WildRares = 0
 	err = dataStore.UpdatePlayer(player)
 	assert.NoError(t, err, "failed to store player")
 
@@ -236,6 +340,7 @@ func TestInsertPairings(t *testing.T) {
 
 func TestGetPairing_Player1(t *testing.T) {
 	setupTest()
+	startLeague(t, 1)
 
 	playerIDs := make([]string, 8)
 	for i := range playerIDs {
@@ -259,12 +364,13 @@ func TestGetPairing_Player1(t *testing.T) {
 
 	pairing, err := dataStore.GetPairing(playerIDs[2])
 	assert.NoError(t, err, "failed to get pairing")
-	assert.Equal(t, pairing, pairings[1], "pairing did not match")
+	assert.Equal(t, withoutCreatedAt(pairing), withoutCreatedAt(pairings[1]), "pairing did not match")
 	teardownTest()
 }
 
 func TestGetPairing_Player2(t *testing.T) {
 	setupTest()
+	startLeague(t, 1)
 
 	playerIDs := make([]string, 8)
 	for i := range playerIDs {
@@ -288,12 +394,32 @@ func TestGetPairing_Player2(t *testing.T) {
 
 	pairing, err := dataStore.GetPairing(playerIDs[5])
 	assert.NoError(t, err, "failed to get pairing")
-	assert.Equal(t, pairing, pairings[2], "pairing did not match")
+	assert.Equal(t, withoutCreatedAt(pairing), withoutCreatedAt(pairings[2]), "pairing did not match")
+	teardownTest()
+}
+
+func TestGetAllPairings(t *testing.T) {
+	setupTest()
+
+	pairings := []Pairing{
+		{Round: 0, Player1: "test_player_a", Player2: "test_player_b", Wins1: 2},
+		{Round: 1, Player1: "test_player_b", Player2: "test_player_a", Flagged: true},
+	}
+	err := dataStore.StorePairings(pairings)
+	assert.NoError(t, err, "failed to store pairings")
+
+	storedPairings, err := dataStore.GetAllPairings()
+	assert.NoError(t, err, "failed to get all pairings")
+	assert.Len(t, storedPairings, len(pairings), "expected every pairing")
+	for i := range pairings {
+		assert.Equal(t, withoutCreatedAt(pairings[i]), withoutCreatedAt(storedPairings[i]), "pairing did not match")
+	}
 	teardownTest()
 }
 
 func TestUpdatePairing(t *testing.T) {
 	setupTest()
+	startLeague(t, 1)
 
 	playerID := "test_player" + strconv.FormatInt(time.Now().UnixNano(), 10)
 	playerID2 := "test_player" + strconv.FormatInt(time.Now().UnixNano(), 10)
@@ -317,7 +443,154 @@ func TestUpdatePairing(t *testing.T) {
 
 	storedPairing, err := dataStore.GetPairing(playerID2)
 	assert.NoError(t, err, "failed to get pairing")
-	assert.Equal(t, pairings[0], storedPairing, "pairing did not match")
+	assert.Equal(t, withoutCreatedAt(pairings[0]), withoutCreatedAt(storedPairing), "pairing did not match")
+	teardownTest()
+}
+
+func TestFlagPairing(t *testing.T) {
+	setupTest()
+	startLeague(t, 1)
+
+	playerID := "test_player" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	playerID2 := "test_player" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	pairings := []Pairing{
+		{Round: 1, Player1: playerID, Player2: playerID2},
+	}
+
+	err := dataStore.StorePairings(pairings)
+	assert.NoError(t, err, "failed to store pairings")
+
+	err = dataStore.FlagPairing(pairings[0])
+	assert.NoError(t, err, "failed to flag pairing")
+
+	storedPairing, err := dataStore.GetPairing(playerID)
+	assert.NoError(t, err, "failed to get pairing")
+	assert.True(t, storedPairing.Flagged, "pairing was not flagged")
+
+	pairings[0].Draws = 3
+	err = dataStore.UpdatePairing(pairings[0])
+	assert.NoError(t, err, "failed to report flagged pairing")
+
+	err = dataStore.FlagPairing(pairings[0])
+	assert.ErrorIs(t, err, ErrPairingNotFound, "flagging a reported pairing shouldn't work")
+	teardownTest()
+}
+
+func TestPairings_active_league(t *testing.T) {
+	setupTest()
+
+	earlierPairing := Pairing{Round: 1, Player1: "test_player_a", Player2: "test_player_b"}
+	startLeague(t, 1)
+	err := dataStore.StorePairings([]Pairing{earlierPairing})
+	assert.NoError(t, err, "failed to store pairings")
+	err = dataStore.EndLeague()
+	assert.NoError(t, err, "failed to end league")
+
+	startLeague(t, 1)
+	err = dataStore.StorePairings([]Pairing{{Round: 1, Player1: "test_player_c", Player2: "test_player_d"}})
+	assert.NoError(t, err, "failed to store pairings")
+
+	storedPairings, err := dataStore.GetPairings(1)
+	assert.NoError(t, err, "failed to get pairings")
+	assert.Len(t, storedPairings, 1, "expected only the pairings of the active league")
+	assert.Equal(t, "test_player_c", storedPairings[0].Player1, "pairing did not match")
+
+	_, err = dataStore.GetPairing("test_player_a")
+	assert.ErrorIs(t, err, ErrPairingNotFound, "expected no pairing of an earlier league")
+
+	earlierPairing.Wins1 = 2
+	err = dataStore.UpdatePairing(earlierPairing)
+	assert.ErrorIs(t, err, ErrPairingNotFound, "reporting a pairing of an earlier league shouldn't work")
+
+	err = dataStore.FlagPairing(earlierPairing)
+	assert.ErrorIs(t, err, ErrPairingNotFound, "flagging a pairing of an earlier league shouldn't work")
+	teardownTest()
+}
+
+func TestStartLeague_first_round(t *testing.T) {
+	setupTest()
+
+	err := dataStore.WithTx(func(tx DataStore) error {
+		err := tx.StartLeague()
+		assert.NoError(t, err, "failed to start league")
+		err = tx.StorePairings([]Pairing{{Round: 0, Player1: "test_player_a", Player2: "test_player_b"}})
+		assert.NoError(t, err, "failed to store pairings")
+		err = tx.AppendLedgerEntries([]LedgerEntry{{PlayerId: "test_player_a", Currency: CurrencyWildPack, Amount: 1, Reason: LedgerReasonStartingPacks, Actor: "system"}})
+		assert.NoError(t, err, "failed to append ledger entries")
+		return nil
+	})
+	assert.NoError(t, err, "failed to run transaction")
+
+	storedPairings, err := dataStore.GetPairings(0)
+	assert.NoError(t, err, "failed to get pairings")
+	assert.Len(t, storedPairings, 1, "expected the pairings stored with the league to belong to it")
+
+	entries, err := dataStore.GetRoundLedgerEntries(0)
+	assert.NoError(t, err, "failed to get round ledger entries")
+	assert.Len(t, entries, 1, "expected the entries stored with the league to belong to it")
+	teardownTest()
+}
+
+func TestNextRound(t *testing.T) {
+	setupTest()
+
+	_, err := dataStore.NextRound()
+	assert.ErrorIs(t, err, ErrNoActiveLeague, "advancing the round without an active league shouldn't work")
+
+	startLeague(t, 0)
+	round, err := dataStore.NextRound()
+	assert.NoError(t, err, "failed to advance round")
+	assert.Equal(t, 1, round, "round did not match")
+
+	storedRound, err := dataStore.GetRound()
+	assert.NoError(t, err, "failed to get round")
+	assert.Equal(t, 1, storedRound, "stored round did not match")
+	teardownTest()
+}
+
+func TestPreviousRound(t *testing.T) {
+	setupTest()
+
+	_, err := dataStore.PreviousRound()
+	assert.ErrorIs(t, err, ErrNoActiveLeague, "returning to the previous round without an active league shouldn't work")
+
+	startLeague(t, 0)
+	_, err = dataStore.PreviousRound()
+	assert.ErrorIs(t, err, ErrNoPreviousRound, "returning to the previous round in the first round shouldn't work")
+
+	_, err = dataStore.NextRound()
+	assert.NoError(t, err, "failed to advance round")
+	round, err := dataStore.PreviousRound()
+	assert.NoError(t, err, "failed to return to previous round")
+	assert.Equal(t, 0, round, "round did not match")
+	teardownTest()
+}
+
+func TestDeletePairings(t *testing.T) {
+	setupTest()
+
+	startLeague(t, 1)
+	err := dataStore.StorePairings([]Pairing{{Round: 1, Player1: "c", Player2: "d"}})
+	assert.NoError(t, err, "failed to store pairings")
+	err = dataStore.EndLeague()
+	assert.NoError(t, err, "failed to end league")
+
+	startLeague(t, 1)
+	pairings := []Pairing{
+		{Round: 0, Player1: "a", Player2: "b"},
+		{Round: 1, Player1: "a", Player2: "b"},
+	}
+	err = dataStore.StorePairings(pairings)
+	assert.NoError(t, err, "failed to store pairings")
+
+	err = dataStore.DeletePairings(1)
+	assert.NoError(t, err, "failed to delete pairings")
+
+	storedPairings, err := dataStore.GetAllPairings()
+	assert.NoError(t, err, "failed to get pairings")
+	assert.Len(t, storedPairings, 2, "expected 2 pairings")
+	assert.Equal(t, 0, storedPairings[0].Round, "expected the pairing of the other round to be kept")
+	assert.Equal(t, "c", storedPairings[1].Player1, "expected the pairing of the earlier league to be kept")
 	teardownTest()
 }
 
@@ -349,6 +622,105 @@ func TestEndRound(t *testing.T) {
 	teardownTest()
 }
 
+func TestStoreLeague(t *testing.T) {
+	setupTest()
+
+	startedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	leagues := []League{
+		{Round: 3, Active: false, StartedAt: &startedAt},
+		{Round: 1, Active: true, StartedAt: &startedAt, RoundDurationSeconds: 3600},
+	}
+	for _, league := range leagues {
+		err := dataStore.StoreLeague(league)
+		assert.NoError(t, err, "failed to store league")
+	}
+
+	storedLeagues, err := dataStore.GetLeagues()
+	assert.NoError(t, err, "failed to get leagues")
+	assert.Len(t, storedLeagues, 2, "expected 2 leagues")
+
+	round, err := dataStore.GetRound()
+	assert.NoError(t, err, "failed to get round")
+	assert.Equal(t, 1, round, "round of the active league did not match")
+	teardownTest()
+}
+
+func TestUpdateLeague(t *testing.T) {
+	setupTest()
+
+	_, err := dataStore.GetLeague()
+	assert.ErrorIs(t, err, ErrNoActiveLeague, "getting a league without an active league shouldn't work")
+
+	err = dataStore.StartLeague()
+	assert.NoError(t, err, "failed to start league")
+
+	league, err := dataStore.GetLeague()
+	assert.NoError(t, err, "failed to get league")
+	assert.Nil(t, league.RoundDeadline, "new league shouldn't have a deadline")
+
+	deadline := time.Now().Add(time.Hour).Truncate(time.Second)
+	league.RoundDurationSeconds = 3600
+	league.RoundDeadline = &deadline
+	err = dataStore.UpdateLeague(league)
+	assert.NoError(t, err, "failed to update league")
+
+	storedLeague, err := dataStore.GetLeague()
+	assert.NoError(t, err, "failed to get league")
+	assert.Equal(t, time.Hour, storedLeague.RoundDuration(), "round duration did not match")
+	assert.True(t, deadline.Equal(*storedLeague.RoundDeadline), "deadline did not match")
+	teardownTest()
+}
+
+func TestGetAdmins(t *testing.T) {
+	setupTest()
+
+	adminID := "test_admin" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	err := dataStore.MakeAdmin(adminID)
+	assert.NoError(t, err, "failed to set admin status")
+
+	adminIDs, err := dataStore.GetAdmins()
+	assert.NoError(t, err, "failed to get admins")
+	assert.Contains(t, adminIDs, adminID, "admin is missing")
+	teardownTest()
+}
+
+func TestStoreSets(t *testing.T) {
+	setupTest()
+
+	unlockedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	err := dataStore.StoreSets([]Set{{SetCode: "IKO", UnlockedAt: unlockedAt}})
+	assert.NoError(t, err, "failed to store sets")
+
+	err = dataStore.UnlockSet("M21", 1)
+	assert.NoError(t, err, "failed to unlock set")
+
+	sets, err := dataStore.GetSets()
+	assert.NoError(t, err, "failed to get sets")
+	assert.Len(t, sets, 2, "expected 2 sets")
+	assert.Equal(t, "IKO", sets[0].SetCode, "expected the set unlocked first")
+	assert.True(t, unlockedAt.Equal(sets[0].UnlockedAt), "unlock time did not match")
+	assert.Equal(t, 1, sets[1].Round, "round did not match")
+	teardownTest()
+}
+
+func TestLockSet(t *testing.T) {
+	setupTest()
+
+	err := dataStore.UnlockSet("IKO", 0)
+	assert.NoError(t, err, "failed to unlock set")
+	err = dataStore.UnlockSet("M21", 1)
+	assert.NoError(t, err, "failed to unlock set")
+
+	err = dataStore.LockSet("M21")
+	assert.NoError(t, err, "failed to lock set")
+
+	sets, err := dataStore.GetSets()
+	assert.NoError(t, err, "failed to get sets")
+	assert.Len(t, sets, 1, "expected 1 set")
+	assert.Equal(t, "IKO", sets[0].SetCode, "expected the other set to be kept")
+	teardownTest()
+}
+
 func TestMakeAdmin(t *testing.T) {
 	setupTest()
 
@@ -378,3 +750,159 @@ func TestIsAdmin(t *testing.T) {
 
 	teardownTest()
 }
+
+func TestLedger(t *testing.T) {
+	setupTest()
+
+	playerID := "test_player" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	entries := []LedgerEntry{
+		{
+			PlayerId: playerID,
+			Currency: WildCardCurrency(RarityRare),
+			Amount:   1,
+			Reason:   LedgerReasonRoundReward,
+			Round:    0,
+			Actor:    "system",
+		},
+		{
+			PlayerId: playerID,
+			Currency: WildCardCurrency(RarityRare),
+			Amount:   -1,
+			Reason:   LedgerReasonRedemption,
+			Round:    0,
+			Actor:    playerID,
+		},
+	}
+
+	err := dataStore.AppendLedgerEntries(entries)
+	assert.NoError(t, err, "failed to append ledger entries")
+
+	storedEntries, err := dataStore.GetLedgerEntries(playerID)
+	assert.NoError(t, err, "failed to get ledger entries")
+	assert.Len(t, storedEntries, 2, "expected 2 ledger entries")
+	assert.Equal(t, LedgerReasonRoundReward, storedEntries[0].Reason, "expected oldest entry first")
+	assert.Equal(t, -1, storedEntries[1].Amount, "amount did not match")
+	teardownTest()
+}
+
+func TestGetRoundLedgerEntries(t *testing.T) {
+	setupTest()
+
+	entries := []LedgerEntry{
+		{PlayerId: "a", Currency: CurrencyWildPack, Amount: 10, Reason: LedgerReasonRoundPacks, Round: 1, Actor: "system"},
+		{PlayerId: "b", Currency: CurrencyWildPack, Amount: 3, Reason: LedgerReasonStartingPacks, Round: 0, Actor: "system"},
+	}
+	err := dataStore.AppendLedgerEntries(entries)
+	assert.NoError(t, err, "failed to append ledger entries")
+
+	startLeague(t, 1)
+	entries = []LedgerEntry{
+		{PlayerId: "a", Currency: CurrencyWildPack, Amount: 10, Reason: LedgerReasonRoundPacks, Round: 1, Actor: "system"},
+		{PlayerId: "b", Currency: CurrencyWildPack, Amount: -1, Reason: LedgerReasonRedemption, Round: 1, Actor: "b"},
+	}
+	err = dataStore.AppendLedgerEntries(entries)
+	assert.NoError(t, err, "failed to append ledger entries")
+
+	storedEntries, err := dataStore.GetRoundLedgerEntries(1)
+	assert.NoError(t, err, "failed to get ledger entries")
+	assert.Len(t, storedEntries, 2, "expected only the entries of the active league")
+	assert.Equal(t, "a", storedEntries[0].PlayerId, "expected oldest entry first")
+	assert.Equal(t, -1, storedEntries[1].Amount, "amount did not match")
+	teardownTest()
+}
+
+func TestPoolLedger(t *testing.T) {
+	setupTest()
+
+	playerID := "test_player" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	startLeague(t, 1)
+	entries := []PoolEntry{
+		{PlayerId: playerID, Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 2, Reason: LedgerReasonStartingPacks, Round: 0, Actor: "system"},
+		{PlayerId: playerID, Name: "Lurrus of the Dream-Den", Set: "IKO", CollectorNumber: "226", Foil: true, Count: 1, Reason: LedgerReasonRedemption, Round: 1, Actor: playerID},
+		{PlayerId: "other", Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: -1, Reason: LedgerReasonTrade, Round: 1, Actor: "other"},
+	}
+	err := dataStore.AppendPoolEntries(entries)
+	assert.NoError(t, err, "failed to append pool entries")
+
+	storedEntries, err := dataStore.GetPoolEntries(playerID)
+	assert.NoError(t, err, "failed to get pool entries")
+	assert.Len(t, storedEntries, 2, "expected 2 pool entries")
+	assert.Equal(t, LedgerReasonStartingPacks, storedEntries[0].Reason, "expected oldest entry first")
+	assert.True(t, storedEntries[1].Foil, "foil did not match")
+
+	roundEntries, err := dataStore.GetRoundPoolEntries(1)
+	assert.NoError(t, err, "failed to get pool entries of round")
+	assert.Len(t, roundEntries, 2, "expected 2 pool entries in round 1")
+	assert.Equal(t, -1, roundEntries[1].Count, "count did not match")
+	teardownTest()
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	setupTest()
+
+	now := time.Now()
+	deliveries := []WebhookDelivery{
+		{URL: "http://localhost/a", Event: "player_joined", Payload: "{}", Status: WebhookDeliveryPending, NextAttemptAt: now.Add(-time.Minute)},
+		{URL: "http://localhost/b", Event: "player_joined", Payload: "{}", Status: WebhookDeliveryPending, NextAttemptAt: now.Add(time.Minute)},
+	}
+	err := dataStore.CreateWebhookDeliveries(deliveries)
+	assert.NoError(t, err, "failed to create webhook deliveries")
+
+	due, err := dataStore.GetDueWebhookDeliveries(now, 10)
+	assert.NoError(t, err, "failed to get due webhook deliveries")
+	assert.Len(t, due, 1, "expected only the first delivery to be due")
+	assert.Equal(t, "http://localhost/a", due[0].URL, "due delivery did not match")
+
+	due[0].Status = WebhookDeliveryDelivered
+	due[0].Attempts = 1
+	due[0].DeliveredAt = &now
+	err = dataStore.UpdateWebhookDelivery(due[0])
+	assert.NoError(t, err, "failed to update webhook delivery")
+
+	due, err = dataStore.GetDueWebhookDeliveries(now.Add(time.Hour), 10)
+	assert.NoError(t, err, "failed to get due webhook deliveries")
+	assert.Len(t, due, 1, "delivered webhooks shouldn't be due")
+	assert.Equal(t, "http://localhost/b", due[0].URL, "due delivery did not match")
+	teardownTest()
+}
+
+func TestWithTx_commit(t *testing.T) {
+	setupTest()
+
+	playerID := "test_player" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	err := dataStore.WithTx(func(tx DataStore) error {
+		return tx.UpdatePlayer(Player{Id: playerID, WildPacks: 3})
+	})
+	assert.NoError(t, err, "failed to commit transaction")
+
+	storedPlayer, err := dataStore.GetPlayer(playerID)
+	assert.NoError(t, err, "failed to get player")
+	assert.Equal(t, 3, storedPlayer.WildPacks, "wild packs did not match")
+	teardownTest()
+}
+
+func TestWithTx_rollback(t *testing.T) {
+	setupTest()
+
+	playerID := "test_player" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	err := dataStore.WithTx(func(tx DataStore) error {
+		err := tx.UpdatePlayer(Player{Id: playerID, WildPacks: 3})
+		assert.NoError(t, err, "failed to store player")
+
+		return tx.StartLeague()
+	})
+	assert.NoError(t, err, "failed to start league")
+
+	err = dataStore.WithTx(func(tx DataStore) error {
+		err := tx.StoreCards(playerID, []Card{{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1"}})
+		assert.NoError(t, err, "failed to store cards")
+
+		return tx.StartLeague()
+	})
+	assert.ErrorIs(t, err, ErrLeagueAlreadyOngoing, "starting a second league shouldn't work")
+
+	storedCards, err := dataStore.GetCards(playerID)
+	assert.NoError(t, err, "failed to get cards")
+	assert.Empty(t, storedCards, "expected cards to be rolled back")
+	teardownTest()
+}
@@ -0,0 +1,747 @@
+// Package repositorytest provides an in-memory datastore for the tests of every package using the repository.
+package repositorytest
+
+import (
+	"cmp"
+	"fmt"
+	"progression/repository"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryDataStore keeps the complete state of the league in memory and behaves like the postgres datastore.
+// Transactions are serialized and rolled back by restoring the state from before the transaction.
+// Operations outside of transactions are not isolated from them.
+type MemoryDataStore struct {
+	// txMutex serializes transactions, mutex guards the state.
+	txMutex sync.Mutex
+	mutex   sync.Mutex
+	state   state
+}
+
+type state struct {
+	leagues    []repository.League
+	players    []repository.Player
+	cards      map[string][]repository.Card
+	pairings   []repository.Pairing
+	admins     []string
+	bans       []repository.Ban
+	sets       []repository.Set
+	ledger     []repository.LedgerEntry
+	pool       []repository.PoolEntry
+	trades     []repository.Trade
+	deliveries []repository.WebhookDelivery
+}
+
+// clone returns a copy of the state, which shares no slices or maps with the original.
+func (s state) clone() state {
+	cards := make(map[string][]repository.Card, len(s.cards))
+	for userID, userCards := range s.cards {
+		cards[userID] = slices.Clone(userCards)
+	}
+
+	trades := slices.Clone(s.trades)
+	for i := range trades {
+		trades[i].Items = slices.Clone(trades[i].Items)
+	}
+
+	return state{
+		leagues:    slices.Clone(s.leagues),
+		players:    slices.Clone(s.players),
+		cards:      cards,
+		pairings:   slices.Clone(s.pairings),
+		admins:     slices.Clone(s.admins),
+		bans:       slices.Clone(s.bans),
+		sets:       slices.Clone(s.sets),
+		ledger:     slices.Clone(s.ledger),
+		pool:       slices.Clone(s.pool),
+		trades:     trades,
+		deliveries: slices.Clone(s.deliveries),
+	}
+}
+
+func NewMemoryDataStore() *MemoryDataStore {
+	return &MemoryDataStore{state: state{cards: make(map[string][]repository.Card)}}
+}
+
+// memoryTx is the datastore passed to transactions. Nested transactions are rolled back on their own, like savepoints.
+type memoryTx struct {
+	*MemoryDataStore
+}
+
+func (t memoryTx) WithTx(f func(tx repository.DataStore) error) error {
+	return t.runTx(f)
+}
+
+func (m *MemoryDataStore) Connect() error {
+	return nil
+}
+
+func (m *MemoryDataStore) WithTx(f func(tx repository.DataStore) error) error {
+	m.txMutex.Lock()
+	defer m.txMutex.Unlock()
+
+	return m.runTx(f)
+}
+
+func (m *MemoryDataStore) runTx(f func(tx repository.DataStore) error) error {
+	m.mutex.Lock()
+	saved := m.state.clone()
+	m.mutex.Unlock()
+
+	err := f(memoryTx{m})
+	if err != nil {
+		m.mutex.Lock()
+		m.state = saved
+		m.mutex.Unlock()
+	}
+	return err
+}
+
+// AddCards adds the cards to the player's card pool. Unlike StoreCards, it adds as many copies of every card as its count.
+func (m *MemoryDataStore) AddCards(userID string, cards ...repository.Card) {
+	var copies []repository.Card
+	for _, card := range cards {
+		for range card.Count {
+			copies = append(copies, card)
+		}
+	}
+	_ = m.StoreCards(userID, copies)
+}
+
+// WebhookDeliveries returns every delivery in the delivery log, oldest first.
+func (m *MemoryDataStore) WebhookDeliveries() []repository.WebhookDelivery {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return slices.Clone(m.state.deliveries)
+}
+
+// activeLeague returns the index of the active league or -1, if no league is active. The caller has to hold the mutex.
+func (m *MemoryDataStore) activeLeague() int {
+	return slices.IndexFunc(m.state.leagues, func(league repository.League) bool { return league.Active })
+}
+
+// inActiveLeague returns whether something created at the given time belongs to the active league. The caller has to hold the mutex.
+func (m *MemoryDataStore) inActiveLeague(createdAt time.Time) bool {
+	idx := m.activeLeague()
+	if idx == -1 || m.state.leagues[idx].StartedAt == nil {
+		return false
+	}
+	return !createdAt.Before(*m.state.leagues[idx].StartedAt)
+}
+
+// createdAt returns the given time or the current time, if it is zero, like the postgres datastore does for new rows.
+func createdAt(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Now()
+	}
+	return t
+}
+
+func (m *MemoryDataStore) StartLeague() error {
+	const errMsg = "failed to start league: %w"
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.activeLeague() != -1 {
+		return fmt.Errorf(errMsg, repository.ErrLeagueAlreadyOngoing)
+	}
+
+	now := time.Now()
+	m.state.leagues = append(m.state.leagues, repository.League{Round: 0, Active: true, StartedAt: &now})
+	return nil
+}
+
+func (m *MemoryDataStore) EndLeague() error {
+	const errMsg = "failed to end league: %w"
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.activeLeague()
+	if idx == -1 {
+		return fmt.Errorf(errMsg, repository.ErrNoActiveLeague)
+	}
+
+	m.state.leagues[idx].Active = false
+	return nil
+}
+
+func (m *MemoryDataStore) NextRound() (int, error) {
+	const errMsg = "failed to advance round: %w"
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.activeLeague()
+	if idx == -1 {
+		return 0, fmt.Errorf(errMsg, repository.ErrNoActiveLeague)
+	}
+
+	m.state.leagues[idx].Round++
+	return m.state.leagues[idx].Round, nil
+}
+
+func (m *MemoryDataStore) PreviousRound() (int, error) {
+	const errMsg = "failed to return to previous round: %w"
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.activeLeague()
+	if idx == -1 {
+		return 0, fmt.Errorf(errMsg, repository.ErrNoActiveLeague)
+	}
+
+	if m.state.leagues[idx].Round == 0 {
+		return 0, fmt.Errorf(errMsg, repository.ErrNoPreviousRound)
+	}
+
+	m.state.leagues[idx].Round--
+	return m.state.leagues[idx].Round, nil
+}
+
+func (m *MemoryDataStore) GetRound() (int, error) {
+	const errMsg = "failed to get current round: %w"
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.activeLeague()
+	if idx == -1 {
+		return 0, fmt.Errorf(errMsg, repository.ErrNoActiveLeague)
+	}
+
+	return m.state.leagues[idx].Round, nil
+}
+
+func (m *MemoryDataStore) GetLeague() (repository.League, error) {
+	const errMsg = "failed to get league: %w"
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.activeLeague()
+	if idx == -1 {
+		return repository.League{}, fmt.Errorf(errMsg, repository.ErrNoActiveLeague)
+	}
+
+	return m.state.leagues[idx], nil
+}
+
+func (m *MemoryDataStore) GetLeagues() ([]repository.League, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return slices.Clone(m.state.leagues), nil
+}
+
+func (m *MemoryDataStore) StoreLeague(league repository.League) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.state.leagues = append(m.state.leagues, league)
+	return nil
+}
+
+func (m *MemoryDataStore) UpdateLeague(league repository.League) error {
+	const errMsg = "failed to update league: %w"
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.activeLeague()
+	if idx == -1 {
+		return fmt.Errorf(errMsg, repository.ErrNoActiveLeague)
+	}
+
+	m.state.leagues[idx].RoundDurationSeconds = league.RoundDurationSeconds
+	m.state.leagues[idx].RoundDeadline = league.RoundDeadline
+	m.state.leagues[idx].RemindedAt = league.RemindedAt
+	return nil
+}
+
+func (m *MemoryDataStore) GetCards(userID string) ([]repository.Card, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return slices.Clone(m.state.cards[userID]), nil
+}
+
+// indexCard returns the index of the card's printing in the player's card pool or -1, if the player doesn't own it. The caller has to hold the mutex.
+func (m *MemoryDataStore) indexCard(userID string, card repository.Card) int {
+	return slices.IndexFunc(m.state.cards[userID], func(stored repository.Card) bool {
+		return stored.Set == card.Set && stored.CollectorNumber == card.CollectorNumber && stored.Foil == card.Foil
+	})
+}
+
+func (m *MemoryDataStore) StoreCards(userID string, cards []repository.Card) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, card := range cards {
+		idx := m.indexCard(userID, card)
+		if idx == -1 {
+			card.Count = 1
+			m.state.cards[userID] = append(m.state.cards[userID], card)
+			continue
+		}
+		m.state.cards[userID][idx].Count++
+	}
+	return nil
+}
+
+func (m *MemoryDataStore) RemoveCards(userID string, card repository.Card, count int) error {
+	const errMsg = "failed to remove cards: %w"
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.indexCard(userID, card)
+	if idx == -1 || m.state.cards[userID][idx].Count < count {
+		return fmt.Errorf(errMsg, repository.ErrCardNotInPool)
+	}
+
+	m.state.cards[userID][idx].Count -= count
+	if m.state.cards[userID][idx].Count == 0 {
+		m.state.cards[userID] = slices.Delete(m.state.cards[userID], idx, idx+1)
+	}
+	return nil
+}
+
+func (m *MemoryDataStore) GetAllPlayers() ([]repository.Player, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var players []repository.Player
+	for _, player := range m.state.players {
+		if !player.Dropped {
+			players = append(players, player)
+		}
+	}
+	return players, nil
+}
+
+func (m *MemoryDataStore) GetDroppedPlayers() ([]repository.Player, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var players []repository.Player
+	for _, player := range m.state.players {
+		if player.Dropped {
+			players = append(players, player)
+		}
+	}
+	return players, nil
+}
+
+// indexPlayer returns the index of the player or -1, if the player doesn't exist. The caller has to hold the mutex.
+func (m *MemoryDataStore) indexPlayer(userID string) int {
+	return slices.IndexFunc(m.state.players, func(player repository.Player) bool { return player.Id == userID })
+}
+
+func (m *MemoryDataStore) GetPlayer(userID string) (repository.Player, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.indexPlayer(userID)
+	if idx == -1 {
+		return repository.Player{}, repository.ErrPlayerNotFound
+	}
+	return m.state.players[idx], nil
+}
+
+// GetPlayerForUpdate returns the player like GetPlayer. No lock is needed, since transactions are serialized.
+func (m *MemoryDataStore) GetPlayerForUpdate(userID string) (repository.Player, error) {
+	return m.GetPlayer(userID)
+}
+
+func (m *MemoryDataStore) UpdatePlayer(player repository.Player) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.indexPlayer(player.Id)
+	if idx == -1 {
+		m.state.players = append(m.state.players, player)
+		return nil
+	}
+	m.state.players[idx] = player
+	return nil
+}
+
+func (m *MemoryDataStore) DropPlayer(userID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.indexPlayer(userID)
+	if idx == -1 {
+		return repository.ErrPlayerNotFound
+	}
+	m.state.players[idx].Dropped = true
+	return nil
+}
+
+func (m *MemoryDataStore) GetPairing(userID string) (repository.Pairing, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.activeLeague()
+	if idx == -1 {
+		return repository.Pairing{}, repository.ErrPairingNotFound
+	}
+
+	round := m.state.leagues[idx].Round
+	for _, pairing := range m.state.pairings {
+		if pairing.Round == round && m.inActiveLeague(pairing.CreatedAt) && (pairing.Player1 == userID || pairing.Player2 == userID) {
+			return pairing, nil
+		}
+	}
+	return repository.Pairing{}, repository.ErrPairingNotFound
+}
+
+func (m *MemoryDataStore) GetPairings(round int) ([]repository.Pairing, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var pairings []repository.Pairing
+	for _, pairing := range m.state.pairings {
+		if pairing.Round == round && m.inActiveLeague(pairing.CreatedAt) {
+			pairings = append(pairings, pairing)
+		}
+	}
+	return pairings, nil
+}
+
+func (m *MemoryDataStore) GetAllPairings() ([]repository.Pairing, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	pairings := slices.Clone(m.state.pairings)
+	slices.SortStableFunc(pairings, func(a, b repository.Pairing) int {
+		return cmp.Or(cmp.Compare(a.Round, b.Round), cmp.Compare(a.Player1, b.Player1), cmp.Compare(a.Player2, b.Player2))
+	})
+	return pairings, nil
+}
+
+func (m *MemoryDataStore) StorePairings(pairings []repository.Pairing) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, pairing := range pairings {
+		pairing.CreatedAt = createdAt(pairing.CreatedAt)
+		m.state.pairings = append(m.state.pairings, pairing)
+	}
+	return nil
+}
+
+// indexUnreportedPairing returns the index of the given pairing of the active league, if it hasn't been reported yet, or -1 otherwise. The caller has to hold the mutex.
+func (m *MemoryDataStore) indexUnreportedPairing(pairing repository.Pairing) int {
+	return slices.IndexFunc(m.state.pairings, func(stored repository.Pairing) bool {
+		return stored.Round == pairing.Round && stored.Player1 == pairing.Player1 && stored.Player2 == pairing.Player2 &&
+			stored.Wins1 == 0 && stored.Wins2 == 0 && stored.Draws == 0 && m.inActiveLeague(stored.CreatedAt)
+	})
+}
+
+func (m *MemoryDataStore) UpdatePairing(pairing repository.Pairing) error {
+	const errMsg = "failed to update pairing: %w"
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.indexUnreportedPairing(pairing)
+	if idx == -1 {
+		return fmt.Errorf(errMsg, repository.ErrPairingNotFound)
+	}
+
+	m.state.pairings[idx].Wins1 = pairing.Wins1
+	m.state.pairings[idx].Wins2 = pairing.Wins2
+	m.state.pairings[idx].Draws = pairing.Draws
+	return nil
+}
+
+func (m *MemoryDataStore) DeletePairings(round int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.state.pairings = slices.DeleteFunc(m.state.pairings, func(pairing repository.Pairing) bool {
+		return pairing.Round == round && m.inActiveLeague(pairing.CreatedAt)
+	})
+	return nil
+}
+
+func (m *MemoryDataStore) FlagPairing(pairing repository.Pairing) error {
+	const errMsg = "failed to flag pairing: %w"
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.indexUnreportedPairing(pairing)
+	if idx == -1 {
+		return fmt.Errorf(errMsg, repository.ErrPairingNotFound)
+	}
+
+	m.state.pairings[idx].Flagged = true
+	return nil
+}
+
+func (m *MemoryDataStore) IsAdmin(userID string) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return slices.Contains(m.state.admins, userID), nil
+}
+
+func (m *MemoryDataStore) MakeAdmin(userID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.state.admins = append(m.state.admins, userID)
+	return nil
+}
+
+func (m *MemoryDataStore) GetAdmins() ([]string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	adminIDs := slices.Clone(m.state.admins)
+	slices.Sort(adminIDs)
+	return adminIDs, nil
+}
+
+func (m *MemoryDataStore) GetBannedCards() ([]repository.Ban, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return slices.Clone(m.state.bans), nil
+}
+
+func (m *MemoryDataStore) BanCard(cardName string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.state.bans = append(m.state.bans, repository.Ban{CardName: cardName})
+	return nil
+}
+
+func (m *MemoryDataStore) UnbanCard(cardName string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.state.bans = slices.DeleteFunc(m.state.bans, func(ban repository.Ban) bool { return ban.CardName == cardName })
+	return nil
+}
+
+func (m *MemoryDataStore) GetSets() ([]repository.Set, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	sets := slices.Clone(m.state.sets)
+	slices.SortStableFunc(sets, func(a, b repository.Set) int {
+		return cmp.Or(a.UnlockedAt.Compare(b.UnlockedAt), cmp.Compare(a.SetCode, b.SetCode))
+	})
+	return sets, nil
+}
+
+func (m *MemoryDataStore) UnlockSet(setCode string, round int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.state.sets = append(m.state.sets, repository.Set{SetCode: setCode, UnlockedAt: time.Now(), Round: round})
+	return nil
+}
+
+func (m *MemoryDataStore) LockSet(setCode string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.state.sets = slices.DeleteFunc(m.state.sets, func(set repository.Set) bool { return set.SetCode == setCode })
+	return nil
+}
+
+func (m *MemoryDataStore) StoreSets(sets []repository.Set) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.state.sets = append(m.state.sets, sets...)
+	return nil
+}
+
+func (m *MemoryDataStore) AppendLedgerEntries(entries []repository.LedgerEntry) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, entry := range entries {
+		entry.Id = len(m.state.ledger) + 1
+		entry.CreatedAt = createdAt(entry.CreatedAt)
+		m.state.ledger = append(m.state.ledger, entry)
+	}
+	return nil
+}
+
+func (m *MemoryDataStore) GetLedgerEntries(userID string) ([]repository.LedgerEntry, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var entries []repository.LedgerEntry
+	for _, entry := range m.state.ledger {
+		if entry.PlayerId == userID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *MemoryDataStore) GetRoundLedgerEntries(round int) ([]repository.LedgerEntry, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var entries []repository.LedgerEntry
+	for _, entry := range m.state.ledger {
+		if entry.Round == round && m.inActiveLeague(entry.CreatedAt) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *MemoryDataStore) AppendPoolEntries(entries []repository.PoolEntry) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, entry := range entries {
+		entry.Id = len(m.state.pool) + 1
+		entry.CreatedAt = createdAt(entry.CreatedAt)
+		m.state.pool = append(m.state.pool, entry)
+	}
+	return nil
+}
+
+func (m *MemoryDataStore) GetPoolEntries(userID string) ([]repository.PoolEntry, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var entries []repository.PoolEntry
+	for _, entry := range m.state.pool {
+		if entry.PlayerId == userID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *MemoryDataStore) GetRoundPoolEntries(round int) ([]repository.PoolEntry, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var entries []repository.PoolEntry
+	for _, entry := range m.state.pool {
+		if entry.Round == round && m.inActiveLeague(entry.CreatedAt) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *MemoryDataStore) CreateTrade(trade repository.Trade) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	trade.Id = len(m.state.trades) + 1
+	trade.CreatedAt = createdAt(trade.CreatedAt)
+	trade.Items = slices.Clone(trade.Items)
+	for i := range trade.Items {
+		trade.Items[i].TradeId = trade.Id
+	}
+	m.state.trades = append(m.state.trades, trade)
+	return trade.Id, nil
+}
+
+func (m *MemoryDataStore) GetTrade(tradeID int) (repository.Trade, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if tradeID < 1 || tradeID > len(m.state.trades) {
+		return repository.Trade{}, repository.ErrTradeNotFound
+	}
+
+	trade := m.state.trades[tradeID-1]
+	trade.Items = slices.Clone(trade.Items)
+	return trade, nil
+}
+
+func (m *MemoryDataStore) UpdateTradeStatus(tradeID int, expected, status repository.TradeStatus) error {
+	const errMsg = "failed to update trade status: %w"
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if tradeID < 1 || tradeID > len(m.state.trades) || m.state.trades[tradeID-1].Status != expected {
+		return fmt.Errorf(errMsg, repository.ErrTradeNotFound)
+	}
+
+	m.state.trades[tradeID-1].Status = status
+	return nil
+}
+
+func (m *MemoryDataStore) CountTrades(userID string, round int, status repository.TradeStatus) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	count := 0
+	for _, trade := range m.state.trades {
+		if trade.Round == round && trade.Status == status && (trade.Proposer == userID || trade.Recipient == userID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MemoryDataStore) CreateWebhookDeliveries(deliveries []repository.WebhookDelivery) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, delivery := range deliveries {
+		delivery.Id = len(m.state.deliveries) + 1
+		delivery.CreatedAt = createdAt(delivery.CreatedAt)
+		m.state.deliveries = append(m.state.deliveries, delivery)
+	}
+	return nil
+}
+
+func (m *MemoryDataStore) GetDueWebhookDeliveries(now time.Time, limit int) ([]repository.WebhookDelivery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var due []repository.WebhookDelivery
+	for _, delivery := range m.state.deliveries {
+		if len(due) == limit {
+			break
+		}
+		if delivery.Status == repository.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (m *MemoryDataStore) UpdateWebhookDelivery(delivery repository.WebhookDelivery) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := slices.IndexFunc(m.state.deliveries, func(stored repository.WebhookDelivery) bool { return stored.Id == delivery.Id })
+	if idx == -1 {
+		return nil
+	}
+
+	stored := &m.state.deliveries[idx]
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.NextAttemptAt = delivery.NextAttemptAt
+	stored.ResponseCode = delivery.ResponseCode
+	stored.LastError = delivery.LastError
+	stored.DeliveredAt = delivery.DeliveredAt
+	return nil
+}
@@ -2,17 +2,29 @@ package scryfall
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
 
 	sf "github.com/BlueMonday/go-scryfall"
 )
 
+// maxIdentifiersPerRequest is the maximum number of cards scryfall returns for a single collection request.
+const maxIdentifiersPerRequest = 75
+
 type Client struct {
 	client         *sf.Client
 	defaultOptions sf.SearchCardsOptions
 }
 
+// Printing identifies a single printing of a card.
+type Printing struct {
+	SetCode         string
+	CollectorNumber string
+}
+
 type SearchOptionsModifier func(sf.SearchCardsOptions) sf.SearchCardsOptions
 
 func NewClient() (*Client, error) {
@@ -43,16 +55,76 @@ func (c *Client) SearchCardInSets(ctx context.Context, cardName string, sets []s
 	query := fmt.Sprintf("%s (%s)", cardName, setRestriction)
 	result, err := c.client.SearchCards(ctx, query, options)
 	if err != nil {
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return result.Cards, nil
 }
 
+// GetCard returns the printing of a card matching the given set code and collector number.
+func (c *Client) GetCard(ctx context.Context, setCode string, collectorNumber string) (sf.Card, error) {
+	card, err := c.client.GetCardBySetCodeAndCollectorNumber(ctx, strings.ToLower(setCode), url.PathEscape(collectorNumber))
+	if err != nil {
+		return sf.Card{}, convertError(err)
+	}
+
+	return card, nil
+}
+
+// GetCards returns the printings matching the given set codes and collector numbers.
+// Scryfall is queried in batches of maxIdentifiersPerRequest. Printings, which can't be found, are omitted.
+func (c *Client) GetCards(ctx context.Context, printings []Printing) ([]sf.Card, error) {
+	cards := make([]sf.Card, 0, len(printings))
+	for start := 0; start < len(printings); start += maxIdentifiersPerRequest {
+		batch := printings[start:min(start+maxIdentifiersPerRequest, len(printings))]
+		identifiers := make([]sf.CardIdentifier, len(batch))
+		for i, printing := range batch {
+			identifiers[i] = sf.CardIdentifier{
+				Set:             strings.ToLower(printing.SetCode),
+				CollectorNumber: printing.CollectorNumber,
+			}
+		}
+
+		result, err := c.client.GetCardsByIdentifiers(ctx, identifiers)
+		if err != nil {
+			return nil, convertError(err)
+		}
+
+		cards = append(cards, result.Data...)
+	}
+
+	return cards, nil
+}
+
 func (c *Client) SearchCard(ctx context.Context, cardName string, optionsModifiers ...SearchOptionsModifier) ([]sf.Card, error) {
 	return c.SearchCardInSets(ctx, cardName, nil, optionsModifiers...)
 }
 
+// AutocompleteCardName returns up to 20 full card names, which could be completions of the given partial name.
+func (c *Client) AutocompleteCardName(ctx context.Context, partialName string) ([]string, error) {
+	return c.client.AutocompleteCard(ctx, partialName)
+}
+
+// WithAllPrintings modifies the search options to return every printing of a matching card instead of only one.
+func WithAllPrintings(options sf.SearchCardsOptions) sf.SearchCardsOptions {
+	options.Unique = sf.UniqueModePrints
+	return options
+}
+
+// ExactName returns a search term, which only matches cards with exactly the given name.
+func ExactName(cardName string) string {
+	return fmt.Sprintf("!%q", cardName)
+}
+
+// convertError replaces scryfall's not found error with ErrCardNotFound.
+func convertError(err error) error {
+	var sfErr *sf.Error
+	if errors.As(err, &sfErr) && sfErr.Status == http.StatusNotFound {
+		return ErrCardNotFound
+	}
+	return err
+}
+
 // generateSetRestriction generates a string containing all given sets, which can be appended to a search to limit the results to only valid sets.
 // TODO: the search query has a length limitation of 1000 charaters. To prevent errors for long leagues, we should return multiple strings if needed.
 func generateSetRestriction(sets []string) string {
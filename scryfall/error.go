@@ -4,3 +4,6 @@ import "errors"
 
 // ErrMoreThanOneCardFound is returned when a given name matches more than one card.
 var ErrMoreThanOneCardFound = errors.New("ambiguous name given, more than one card found")
+
+// ErrCardNotFound is returned when no card matches the given search.
+var ErrCardNotFound = errors.New("card not found")
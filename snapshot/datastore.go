@@ -0,0 +1,332 @@
+package snapshot
+
+import (
+	"fmt"
+	"progression/repository"
+	"time"
+)
+
+// Export reads the complete state of the league from the datastore.
+func Export(dataStore repository.DataStore) (Snapshot, error) {
+	const errMsg = "failed to export snapshot: %w"
+
+	snapshot := Snapshot{Version: Version, CreatedAt: time.Now().UTC()}
+	err := dataStore.WithTx(func(tx repository.DataStore) error {
+		var err error
+		snapshot.Leagues, snapshot.Players, err = exportLeagues(tx)
+		if err != nil {
+			return err
+		}
+
+		pairings, err := tx.GetAllPairings()
+		if err != nil {
+			return err
+		}
+		for _, pairing := range pairings {
+			snapshot.Pairings = append(snapshot.Pairings, Pairing(pairing))
+		}
+
+		sets, err := tx.GetSets()
+		if err != nil {
+			return err
+		}
+		for _, set := range sets {
+			snapshot.Sets = append(snapshot.Sets, Set(set))
+		}
+
+		bans, err := tx.GetBannedCards()
+		if err != nil {
+			return err
+		}
+		for _, ban := range bans {
+			snapshot.Bans = append(snapshot.Bans, ban.CardName)
+		}
+
+		snapshot.Admins, err = tx.GetAdmins()
+		return err
+	})
+	if err != nil {
+		return Snapshot{}, fmt.Errorf(errMsg, err)
+	}
+
+	return snapshot, nil
+}
+
+// exportLeagues returns every league and every player including their card pool, ledger and pool ledger.
+func exportLeagues(dataStore repository.DataStore) ([]League, []Player, error) {
+	leagues, err := dataStore.GetLeagues()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var exportedLeagues []League
+	for _, league := range leagues {
+		exportedLeagues = append(exportedLeagues, League(league))
+	}
+
+	players, err := dataStore.GetAllPlayers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	droppedPlayers, err := dataStore.GetDroppedPlayers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var exportedPlayers []Player
+	for _, player := range append(players, droppedPlayers...) {
+		exported := Player{
+			ID:            player.Id,
+			WildCards:     make(map[repository.Rarity]int, len(repository.Rarities)),
+			WildPacks:     player.WildPacks,
+			VaultProgress: player.VaultProgress,
+			Dropped:       player.Dropped,
+		}
+		for _, rarity := range repository.Rarities {
+			exported.WildCards[rarity] = player.WildCards(rarity)
+		}
+
+		cards, err := dataStore.GetCards(player.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, card := range cards {
+			exported.Pool = append(exported.Pool, Card(card))
+		}
+
+		entries, err := dataStore.GetLedgerEntries(player.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, entry := range entries {
+			exported.Ledger = append(exported.Ledger, LedgerEntry{
+				Currency:  entry.Currency,
+				Amount:    entry.Amount,
+				Reason:    entry.Reason,
+				Round:     entry.Round,
+				Actor:     entry.Actor,
+				Note:      entry.Note,
+				CreatedAt: entry.CreatedAt,
+			})
+		}
+
+		poolEntries, err := dataStore.GetPoolEntries(player.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, entry := range poolEntries {
+			exported.PoolLedger = append(exported.PoolLedger, PoolEntry{
+				Card: Card{
+					Name:            entry.Name,
+					Set:             entry.Set,
+					CollectorNumber: entry.CollectorNumber,
+					Foil:            entry.Foil,
+					ScryfallURI:     entry.ScryfallURI,
+					ImageURL:        entry.ImageURL,
+					Count:           entry.Count,
+				},
+				Reason:    entry.Reason,
+				Round:     entry.Round,
+				Actor:     entry.Actor,
+				CreatedAt: entry.CreatedAt,
+			})
+		}
+
+		exportedPlayers = append(exportedPlayers, exported)
+	}
+
+	return exportedLeagues, exportedPlayers, nil
+}
+
+// Import stores the snapshot in the datastore. Everything is stored together or not at all.
+// It returns ErrDataStoreNotEmpty, unless the datastore contains no leagues, players, sets or bans. Existing admins are kept.
+// It returns ErrUnsupportedVersion, if the snapshot has been written in another version of the format.
+func Import(dataStore repository.DataStore, snapshot Snapshot) error {
+	const errMsg = "failed to import snapshot: %w"
+
+	if snapshot.Version != Version {
+		return fmt.Errorf(errMsg, fmt.Errorf("%w %d", ErrUnsupportedVersion, snapshot.Version))
+	}
+
+	err := dataStore.WithTx(func(tx repository.DataStore) error {
+		err := requireEmpty(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, league := range snapshot.Leagues {
+			err = tx.StoreLeague(repository.League(league))
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, player := range snapshot.Players {
+			err = importPlayer(tx, player)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(snapshot.Pairings) != 0 {
+			pairings := make([]repository.Pairing, 0, len(snapshot.Pairings))
+			for _, pairing := range snapshot.Pairings {
+				pairings = append(pairings, repository.Pairing(pairing))
+			}
+
+			err = tx.StorePairings(pairings)
+			if err != nil {
+				return err
+			}
+		}
+
+		sets := make([]repository.Set, 0, len(snapshot.Sets))
+		for _, set := range snapshot.Sets {
+			sets = append(sets, repository.Set(set))
+		}
+		err = tx.StoreSets(sets)
+		if err != nil {
+			return err
+		}
+
+		for _, cardName := range snapshot.Bans {
+			err = tx.BanCard(cardName)
+			if err != nil {
+				return err
+			}
+		}
+
+		return importAdmins(tx, snapshot.Admins)
+	})
+	if err != nil {
+		return fmt.Errorf(errMsg, err)
+	}
+
+	return nil
+}
+
+// requireEmpty returns ErrDataStoreNotEmpty, if the datastore contains any leagues, players, sets or bans.
+func requireEmpty(dataStore repository.DataStore) error {
+	leagues, err := dataStore.GetLeagues()
+	if err != nil {
+		return err
+	}
+
+	players, err := dataStore.GetAllPlayers()
+	if err != nil {
+		return err
+	}
+
+	droppedPlayers, err := dataStore.GetDroppedPlayers()
+	if err != nil {
+		return err
+	}
+
+	sets, err := dataStore.GetSets()
+	if err != nil {
+		return err
+	}
+
+	bans, err := dataStore.GetBannedCards()
+	if err != nil {
+		return err
+	}
+
+	if len(leagues) != 0 || len(players) != 0 || len(droppedPlayers) != 0 || len(sets) != 0 || len(bans) != 0 {
+		return ErrDataStoreNotEmpty
+	}
+
+	return nil
+}
+
+// importPlayer stores the player, their card pool, their ledger and their pool ledger.
+func importPlayer(dataStore repository.DataStore, player Player) error {
+	stored := repository.Player{
+		Id:            player.ID,
+		WildPacks:     player.WildPacks,
+		VaultProgress: player.VaultProgress,
+		Dropped:       player.Dropped,
+	}
+	for rarity, count := range player.WildCards {
+		stored.AddWildCards(rarity, count)
+	}
+
+	err := dataStore.UpdatePlayer(stored)
+	if err != nil {
+		return err
+	}
+
+	// the datastore stores every card of the list as a single copy
+	var copies []repository.Card
+	for _, card := range player.Pool {
+		for range card.Count {
+			copies = append(copies, repository.Card(card))
+		}
+	}
+
+	err = dataStore.StoreCards(player.ID, copies)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]repository.LedgerEntry, 0, len(player.Ledger))
+	for _, entry := range player.Ledger {
+		entries = append(entries, repository.LedgerEntry{
+			PlayerId:  player.ID,
+			Currency:  entry.Currency,
+			Amount:    entry.Amount,
+			Reason:    entry.Reason,
+			Round:     entry.Round,
+			Actor:     entry.Actor,
+			Note:      entry.Note,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+
+	err = dataStore.AppendLedgerEntries(entries)
+	if err != nil {
+		return err
+	}
+
+	poolEntries := make([]repository.PoolEntry, 0, len(player.PoolLedger))
+	for _, entry := range player.PoolLedger {
+		poolEntries = append(poolEntries, repository.PoolEntry{
+			PlayerId:        player.ID,
+			Name:            entry.Card.Name,
+			Set:             entry.Card.Set,
+			CollectorNumber: entry.Card.CollectorNumber,
+			Foil:            entry.Card.Foil,
+			ScryfallURI:     entry.Card.ScryfallURI,
+			ImageURL:        entry.Card.ImageURL,
+			Count:           entry.Card.Count,
+			Reason:          entry.Reason,
+			Round:           entry.Round,
+			Actor:           entry.Actor,
+			CreatedAt:       entry.CreatedAt,
+		})
+	}
+
+	return dataStore.AppendPoolEntries(poolEntries)
+}
+
+// importAdmins makes the given users admins, unless they already are.
+func importAdmins(dataStore repository.DataStore, adminIDs []string) error {
+	for _, adminID := range adminIDs {
+		isAdmin, err := dataStore.IsAdmin(adminID)
+		if err != nil {
+			return err
+		}
+
+		if isAdmin {
+			continue
+		}
+
+		err = dataStore.MakeAdmin(adminID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
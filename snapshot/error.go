@@ -0,0 +1,9 @@
+package snapshot
+
+import "errors"
+
+// ErrUnsupportedVersion is returned when reading a snapshot, which has been written in a different version of the format.
+var ErrUnsupportedVersion = errors.New("unsupported snapshot version")
+
+// ErrDataStoreNotEmpty is returned when importing a snapshot into a datastore, which already contains leagues, players, sets or bans.
+var ErrDataStoreNotEmpty = errors.New("datastore is not empty")
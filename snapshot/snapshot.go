@@ -0,0 +1,120 @@
+// Package snapshot captures the complete state of the league in a versioned JSON document, which can be imported into any datastore.
+// Snapshots serve as backups, to migrate between backends and to reproduce bugs locally.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"progression/repository"
+	"time"
+)
+
+// Version is the version of the snapshot format. It is increased whenever the format changes incompatibly.
+// Version 2 added the pool ledger and the creation times of pairings, which version 1 snapshots can't be migrated to.
+const Version = 2
+
+// Snapshot is the state of the league at the time it has been created.
+// Trades and webhook deliveries are not part of it.
+type Snapshot struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	// Leagues contains every league, oldest first. Only the last one may be active.
+	Leagues  []League  `json:"leagues"`
+	Players  []Player  `json:"players"`
+	Pairings []Pairing `json:"pairings"`
+	Sets     []Set     `json:"sets"`
+	Bans     []string  `json:"bans"`
+	Admins   []string  `json:"admins"`
+}
+
+type League struct {
+	Round                int        `json:"round"`
+	Active               bool       `json:"active"`
+	StartedAt            *time.Time `json:"started_at"`
+	RoundDurationSeconds int        `json:"round_duration_seconds"`
+	RoundDeadline        *time.Time `json:"round_deadline"`
+	RemindedAt           *time.Time `json:"reminded_at"`
+}
+
+// Player is a player including their card pool and the ledger explaining their balance.
+type Player struct {
+	ID            string                    `json:"id"`
+	WildCards     map[repository.Rarity]int `json:"wild_cards"`
+	WildPacks     int                       `json:"wild_packs"`
+	VaultProgress int                       `json:"vault_progress"`
+	Dropped       bool                      `json:"dropped"`
+	Pool          []Card                    `json:"pool"`
+	Ledger        []LedgerEntry             `json:"ledger"`
+	// PoolLedger explains the changes to the card pool, so rounds can be rolled back after restoring the snapshot.
+	PoolLedger []PoolEntry `json:"pool_ledger"`
+}
+
+type Card struct {
+	Name            string `json:"name"`
+	Set             string `json:"set_code"`
+	CollectorNumber string `json:"collector_number"`
+	Foil            bool   `json:"foil"`
+	ScryfallURI     string `json:"scryfall_uri"`
+	ImageURL        string `json:"image_url"`
+	Count           int    `json:"count"`
+}
+
+type LedgerEntry struct {
+	Currency  repository.Currency     `json:"currency"`
+	Amount    int                     `json:"amount"`
+	Reason    repository.LedgerReason `json:"reason"`
+	Round     int                     `json:"round"`
+	Actor     string                  `json:"actor"`
+	Note      string                  `json:"note"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+type PoolEntry struct {
+	Card      Card                    `json:"card"`
+	Reason    repository.LedgerReason `json:"reason"`
+	Round     int                     `json:"round"`
+	Actor     string                  `json:"actor"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+type Pairing struct {
+	Round     int       `json:"round"`
+	Player1   string    `json:"player1"`
+	Player2   string    `json:"player2"`
+	Wins1     int       `json:"wins1"`
+	Wins2     int       `json:"wins2"`
+	Draws     int       `json:"draws"`
+	Flagged   bool      `json:"flagged"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Set struct {
+	SetCode    string    `json:"set_code"`
+	UnlockedAt time.Time `json:"unlocked_at"`
+	Round      int       `json:"round"`
+}
+
+// Write writes the snapshot as indented JSON.
+func Write(w io.Writer, snapshot Snapshot) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snapshot)
+}
+
+// Read reads a snapshot written by Write. It returns ErrUnsupportedVersion, if the snapshot has been written in another version of the format.
+func Read(r io.Reader) (Snapshot, error) {
+	const errMsg = "failed to read snapshot: %w"
+
+	var snapshot Snapshot
+	err := json.NewDecoder(r).Decode(&snapshot)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf(errMsg, err)
+	}
+
+	if snapshot.Version != Version {
+		return Snapshot{}, fmt.Errorf(errMsg, fmt.Errorf("%w %d", ErrUnsupportedVersion, snapshot.Version))
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,139 @@
+package snapshot
+
+import (
+	"bytes"
+	"progression/repository"
+	"progression/repository/repositorytest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLeagueDataStore(t *testing.T) *repositorytest.MemoryDataStore {
+	t.Helper()
+
+	startedAt := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	deadline := startedAt.Add(7 * 24 * time.Hour)
+
+	dataStore := repositorytest.NewMemoryDataStore()
+	require.NoError(t, dataStore.StoreLeague(repository.League{Round: 2, StartedAt: &startedAt}))
+	require.NoError(t, dataStore.StoreLeague(repository.League{Round: 1, Active: true, StartedAt: &startedAt, RoundDurationSeconds: 604800, RoundDeadline: &deadline, RemindedAt: &startedAt}))
+	require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: "a", WildRares: 2, WildPacks: 1, VaultProgress: 13}))
+	require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: "b", WildMythics: 1, Dropped: true}))
+	dataStore.AddCards("a",
+		repository.Card{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 3},
+		repository.Card{Name: "Lurrus of the Dream-Den", Set: "IKO", CollectorNumber: "226", Foil: true, Count: 1},
+	)
+	require.NoError(t, dataStore.AppendLedgerEntries([]repository.LedgerEntry{
+		{PlayerId: "a", Currency: repository.WildCardCurrency(repository.RarityRare), Amount: 2, Reason: repository.LedgerReasonRoundReward, Actor: "system", CreatedAt: startedAt},
+		{PlayerId: "b", Currency: repository.CurrencyWildPack, Amount: 1, Reason: repository.LedgerReasonAdminAdjustment, Round: 1, Actor: "admin", Note: "outage", CreatedAt: startedAt},
+	}))
+	require.NoError(t, dataStore.AppendPoolEntries([]repository.PoolEntry{
+		{PlayerId: "a", Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 3, Reason: repository.LedgerReasonStartingPacks, Actor: "system", CreatedAt: startedAt},
+		{PlayerId: "a", Name: "Lurrus of the Dream-Den", Set: "IKO", CollectorNumber: "226", Foil: true, Count: 1, Reason: repository.LedgerReasonRedemption, Round: 1, Actor: "a", CreatedAt: deadline},
+	}))
+	require.NoError(t, dataStore.StorePairings([]repository.Pairing{
+		{Round: 0, Player1: "a", Player2: "b", Wins1: 2, Wins2: 1, CreatedAt: startedAt},
+		{Round: 1, Player1: "b", Player2: "a", Flagged: true, CreatedAt: deadline},
+	}))
+	require.NoError(t, dataStore.StoreSets([]repository.Set{{SetCode: "IKO", UnlockedAt: startedAt}, {SetCode: "M21", UnlockedAt: deadline, Round: 1}}))
+	require.NoError(t, dataStore.BanCard("Lurrus of the Dream-Den"))
+	require.NoError(t, dataStore.MakeAdmin("admin"))
+	return dataStore
+}
+
+// state is everything the datastore returns about the leagues, which a snapshot has to preserve.
+type state struct {
+	leagues    []repository.League
+	players    []repository.Player
+	cards      [][]repository.Card
+	ledger     [][]repository.LedgerEntry
+	poolLedger [][]repository.PoolEntry
+	pairings   []repository.Pairing
+	sets       []repository.Set
+	bans       []repository.Ban
+	admins     []string
+}
+
+func getState(t *testing.T, dataStore repository.DataStore) state {
+	t.Helper()
+
+	var s state
+	var err error
+	s.leagues, err = dataStore.GetLeagues()
+	require.NoError(t, err)
+	s.players, err = dataStore.GetAllPlayers()
+	require.NoError(t, err)
+	droppedPlayers, err := dataStore.GetDroppedPlayers()
+	require.NoError(t, err)
+	s.players = append(s.players, droppedPlayers...)
+	for _, player := range s.players {
+		cards, err := dataStore.GetCards(player.Id)
+		require.NoError(t, err)
+		s.cards = append(s.cards, cards)
+		entries, err := dataStore.GetLedgerEntries(player.Id)
+		require.NoError(t, err)
+		s.ledger = append(s.ledger, entries)
+		poolEntries, err := dataStore.GetPoolEntries(player.Id)
+		require.NoError(t, err)
+		s.poolLedger = append(s.poolLedger, poolEntries)
+	}
+	s.pairings, err = dataStore.GetAllPairings()
+	require.NoError(t, err)
+	s.sets, err = dataStore.GetSets()
+	require.NoError(t, err)
+	s.bans, err = dataStore.GetBannedCards()
+	require.NoError(t, err)
+	s.admins, err = dataStore.GetAdmins()
+	require.NoError(t, err)
+	return s
+}
+
+func TestExportImport(t *testing.T) {
+	source := newLeagueDataStore(t)
+	snapshot, err := Export(source)
+	require.NoError(t, err)
+
+	var buffer bytes.Buffer
+	err = Write(&buffer, snapshot)
+	require.NoError(t, err)
+
+	read, err := Read(&buffer)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, read)
+
+	target := repositorytest.NewMemoryDataStore()
+	require.NoError(t, target.MakeAdmin("admin"))
+	err = Import(target, read)
+	require.NoError(t, err)
+
+	// the admins are compared, too, so existing admins have to be kept once
+	assert.Equal(t, getState(t, source), getState(t, target))
+}
+
+func TestImport_not_empty(t *testing.T) {
+	snapshot, err := Export(newLeagueDataStore(t))
+	require.NoError(t, err)
+
+	target := repositorytest.NewMemoryDataStore()
+	require.NoError(t, target.StoreSets([]repository.Set{{SetCode: "IKO"}}))
+	err = Import(target, snapshot)
+	assert.ErrorIs(t, err, ErrDataStoreNotEmpty)
+	players, err := target.GetAllPlayers()
+	require.NoError(t, err)
+	assert.Empty(t, players)
+}
+
+func TestRead_unsupported_version(t *testing.T) {
+	_, err := Read(strings.NewReader(`{"version": 1, "leagues": []}`))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestImport_unsupported_version(t *testing.T) {
+	target := repositorytest.NewMemoryDataStore()
+	err := Import(target, Snapshot{Version: 1})
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
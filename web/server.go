@@ -0,0 +1,303 @@
+package web
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"progression/league"
+	"progression/repository"
+	"slices"
+	"strings"
+	"time"
+)
+
+// shutdownTimeout is the time requests in progress have to finish once the server is stopped.
+const shutdownTimeout = 5 * time.Second
+
+//go:embed templates static
+var assets embed.FS
+
+// Config configures the dashboard.
+type Config struct {
+	// ShowPools enables the pool pages. Without it, players' card pools stay private.
+	ShowPools bool
+	// PlayerName returns the name shown for a player. Without it, the player's ID is shown.
+	PlayerName func(userID string) string
+}
+
+// Server serves a dashboard of the league's state, which is easier to read on a phone than discord's messages.
+type Server struct {
+	leagueManager *league.Manager
+	config        Config
+	pages         map[string]*template.Template
+	handler       http.Handler
+}
+
+func New(leagueManager *league.Manager, config Config) (*Server, error) {
+	if config.PlayerName == nil {
+		config.PlayerName = func(userID string) string { return userID }
+	}
+
+	server := &Server{
+		leagueManager: leagueManager,
+		config:        config,
+		pages:         make(map[string]*template.Template),
+	}
+
+	for _, page := range []string{"standings", "rounds", "sets", "bans", "pool"} {
+		tmpl, err := template.ParseFS(assets, "templates/layout.html", "templates/"+page+".html")
+		if err != nil {
+			return nil, err
+		}
+		server.pages[page] = tmpl
+	}
+
+	static, err := fs.Sub(assets, "static")
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", server.getStandings)
+	mux.HandleFunc("GET /rounds", server.getRounds)
+	mux.HandleFunc("GET /sets", server.getSets)
+	mux.HandleFunc("GET /bans", server.getBans)
+	if config.ShowPools {
+		mux.HandleFunc("GET /players/{userID}/pool", server.getPool)
+	}
+	mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(static)))
+	server.handler = mux
+
+	return server, nil
+}
+
+// Handler returns the handler serving every page of the dashboard.
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// ListenAndServe serves the dashboard on the given address until the context is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context, address string) error {
+	server := &http.Server{
+		Addr:              address,
+		Handler:           s.handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("Serving dashboard...", "address", address)
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// page is the data passed to the layout. Data is passed to the page's content, unless a message is shown instead.
+type page struct {
+	Title   string
+	Message string
+	Data    any
+}
+
+type standingRow struct {
+	Rank    int
+	Name    string
+	PoolURL string
+	Points  int
+	Matches string
+	Games   string
+	Dropped bool
+}
+
+type roundView struct {
+	Number   int
+	Pairings []pairingView
+}
+
+type pairingView struct {
+	Player1 string
+	Player2 string
+	Result  string
+	// Status is "reported", "pending" or "flagged".
+	Status string
+}
+
+type poolView struct {
+	Query string
+	Total int
+	Cards []repository.Card
+}
+
+func (s *Server) getStandings(w http.ResponseWriter, _ *http.Request) {
+	standings, err := s.leagueManager.GetStandings()
+	if err != nil {
+		s.renderError(w, "Standings", err)
+		return
+	}
+
+	rows := make([]standingRow, 0, len(standings))
+	for idx, standing := range standings {
+		row := standingRow{
+			Rank:    idx + 1,
+			Name:    s.config.PlayerName(standing.PlayerID),
+			Points:  standing.Points,
+			Matches: fmt.Sprintf("%d-%d-%d", standing.MatchWins, standing.MatchLosses, standing.MatchDraws),
+			Games:   fmt.Sprintf("%d-%d-%d", standing.GameWins, standing.GameLosses, standing.GameDraws),
+			Dropped: standing.Dropped,
+		}
+		if s.config.ShowPools {
+			row.PoolURL = "/players/" + url.PathEscape(standing.PlayerID) + "/pool"
+		}
+		rows = append(rows, row)
+	}
+
+	s.render(w, http.StatusOK, "standings", page{Title: "Standings", Data: rows})
+}
+
+func (s *Server) getRounds(w http.ResponseWriter, _ *http.Request) {
+	round, err := s.leagueManager.GetRound()
+	if err != nil {
+		s.renderError(w, "Rounds", err)
+		return
+	}
+
+	var rounds []roundView
+	for r := round; r >= 0; r-- {
+		pairings, err := s.leagueManager.GetPairings(r)
+		if err != nil {
+			s.renderError(w, "Rounds", err)
+			return
+		}
+
+		view := roundView{Number: r}
+		for _, pairing := range pairings {
+			view.Pairings = append(view.Pairings, s.newPairingView(pairing))
+		}
+		rounds = append(rounds, view)
+	}
+
+	s.render(w, http.StatusOK, "rounds", page{Title: "Rounds", Data: rounds})
+}
+
+func (s *Server) newPairingView(pairing repository.Pairing) pairingView {
+	view := pairingView{
+		Player1: s.config.PlayerName(pairing.Player1),
+		Player2: s.config.PlayerName(pairing.Player2),
+	}
+
+	switch {
+	case pairing.Wins1 != 0 || pairing.Wins2 != 0 || pairing.Draws != 0:
+		view.Result = fmt.Sprintf("%d-%d-%d", pairing.Wins1, pairing.Wins2, pairing.Draws)
+		view.Status = "reported"
+	case pairing.Flagged:
+		view.Result = "flagged"
+		view.Status = "flagged"
+	default:
+		view.Result = "vs"
+		view.Status = "pending"
+	}
+	return view
+}
+
+func (s *Server) getSets(w http.ResponseWriter, _ *http.Request) {
+	sets, err := s.leagueManager.GetSets()
+	if err != nil {
+		s.renderError(w, "Sets", err)
+		return
+	}
+
+	s.render(w, http.StatusOK, "sets", page{Title: "Sets", Data: sets})
+}
+
+func (s *Server) getBans(w http.ResponseWriter, _ *http.Request) {
+	bans, err := s.leagueManager.GetBannedCards()
+	if err != nil {
+		s.renderError(w, "Bans", err)
+		return
+	}
+
+	cardNames := make([]string, 0, len(bans))
+	for _, ban := range bans {
+		cardNames = append(cardNames, ban.CardName)
+	}
+	slices.Sort(cardNames)
+
+	s.render(w, http.StatusOK, "bans", page{Title: "Bans", Data: cardNames})
+}
+
+func (s *Server) getPool(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userID")
+	title := "Pool of " + s.config.PlayerName(userID)
+
+	cards, err := s.leagueManager.GetPlayerCards(userID)
+	if err != nil {
+		s.renderError(w, title, err)
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	view := poolView{Query: query, Cards: filterCards(cards, query)}
+	for _, card := range view.Cards {
+		view.Total += card.Count
+	}
+
+	s.render(w, http.StatusOK, "pool", page{Title: title, Data: view})
+}
+
+// filterCards returns the cards, whose name or set code contains the query, ordered by name. The query is case-insensitive.
+func filterCards(cards []repository.Card, query string) []repository.Card {
+	query = strings.ToLower(query)
+
+	var filtered []repository.Card
+	for _, card := range cards {
+		if strings.Contains(strings.ToLower(card.Name), query) || strings.EqualFold(card.Set, query) {
+			filtered = append(filtered, card)
+		}
+	}
+
+	slices.SortFunc(filtered, func(a, b repository.Card) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.Set, b.Set), strings.Compare(a.CollectorNumber, b.CollectorNumber))
+	})
+	return filtered
+}
+
+// renderError shows a message matching the error. Unexpected errors are logged instead of being exposed.
+func (s *Server) renderError(w http.ResponseWriter, title string, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNoActiveLeague):
+		s.render(w, http.StatusOK, "standings", page{Title: title, Message: "No league is active."})
+	default:
+		slog.Error("Dashboard request failed", "error", err)
+		s.render(w, http.StatusInternalServerError, "standings", page{Title: title, Message: "Something went wrong. Please try again later."})
+	}
+}
+
+// render executes the page's template into a buffer first, so a failing template doesn't send half a page.
+func (s *Server) render(w http.ResponseWriter, status int, name string, data page) {
+	var buffer bytes.Buffer
+	err := s.pages[name].ExecuteTemplate(&buffer, "layout", data)
+	if err != nil {
+		slog.Error("Failed to render dashboard page", "page", name, "error", err)
+		http.Error(w, "Something went wrong. Please try again later.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = buffer.WriteTo(w)
+}
@@ -0,0 +1,112 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"progression/league"
+	"progression/repository"
+	"progression/repository/repositorytest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCards is the card pool of every player.
+var testCards = []repository.Card{
+	{Name: "Lurrus of the Dream-Den", Set: "IKO", CollectorNumber: "226", Count: 1},
+	{Name: "Adaptive Shimmerer", Set: "IKO", CollectorNumber: "1", Count: 2},
+	{Name: "Llanowar Elves", Set: "M19", CollectorNumber: "314", Foil: true, Count: 1},
+}
+
+func newTestServer(t *testing.T, config Config) *Server {
+	t.Helper()
+
+	dataStore := repositorytest.NewMemoryDataStore()
+	// the pairings of an earlier league must not show up
+	require.NoError(t, dataStore.StartLeague())
+	_, err := dataStore.NextRound()
+	require.NoError(t, err)
+	require.NoError(t, dataStore.StorePairings([]repository.Pairing{{Round: 1, Player1: "x", Player2: "y", Wins1: 2}}))
+	require.NoError(t, dataStore.EndLeague())
+
+	require.NoError(t, dataStore.StartLeague())
+	_, err = dataStore.NextRound()
+	require.NoError(t, err)
+	for _, userID := range []string{"a", "b"} {
+		require.NoError(t, dataStore.UpdatePlayer(repository.Player{Id: userID}))
+		dataStore.AddCards(userID, testCards...)
+	}
+	require.NoError(t, dataStore.DropPlayer("b"))
+	require.NoError(t, dataStore.StorePairings([]repository.Pairing{
+		{Round: 0, Player1: "a", Player2: "b", Wins1: 2, Wins2: 1},
+		{Round: 1, Player1: "b", Player2: "a", Flagged: true},
+	}))
+	require.NoError(t, dataStore.StoreSets([]repository.Set{{SetCode: "IKO", UnlockedAt: time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)}}))
+	require.NoError(t, dataStore.BanCard("Lurrus of the Dream-Den"))
+
+	leagueManager := league.NewLeagueManager(dataStore, nil, nil, league.DefaultConfig())
+	server, err := New(leagueManager, config)
+	require.NoError(t, err)
+	return server
+}
+
+func TestServer(t *testing.T) {
+	tests := []struct {
+		name               string
+		path               string
+		expectedStatus     int
+		expectedContents   []string
+		unexpectedContents []string
+	}{
+		{name: "standings", path: "/", expectedStatus: http.StatusOK,
+			expectedContents: []string{"Player a", `<a href="/players/a/pool">`, "2-1-0", `class="dropped"`}},
+		{name: "rounds", path: "/rounds", expectedStatus: http.StatusOK,
+			expectedContents: []string{"Round 0", "Round 1", `<li class="flagged">`, "2-1-0"}, unexpectedContents: []string{"Player x"}},
+		{name: "sets", path: "/sets", expectedStatus: http.StatusOK, expectedContents: []string{"IKO", "1 Jun 2025"}},
+		{name: "bans", path: "/bans", expectedStatus: http.StatusOK, expectedContents: []string{"Lurrus of the Dream-Den"}},
+		{name: "pool", path: "/players/a/pool", expectedStatus: http.StatusOK,
+			expectedContents: []string{"Pool of Player a", "4 cards", "Llanowar Elves", "✨"}},
+		{name: "pool_search", path: "/players/a/pool?q=lurrus", expectedStatus: http.StatusOK,
+			expectedContents: []string{"1 card matching “lurrus”", "Lurrus of the Dream-Den"}},
+		{name: "static", path: "/static/style.css", expectedStatus: http.StatusOK, expectedContents: []string{"--accent"}},
+		{name: "unknown", path: "/unknown", expectedStatus: http.StatusNotFound},
+	}
+
+	server := newTestServer(t, Config{ShowPools: true, PlayerName: func(userID string) string { return "Player " + userID }})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			for _, content := range tt.expectedContents {
+				assert.Contains(t, recorder.Body.String(), content)
+			}
+			for _, content := range tt.unexpectedContents {
+				assert.NotContains(t, recorder.Body.String(), content)
+			}
+		})
+	}
+}
+
+func TestServer_hidden_pools(t *testing.T) {
+	server := newTestServer(t, Config{})
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/players/a/pool", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NotContains(t, recorder.Body.String(), "/pool")
+}
+
+func TestFilterCards(t *testing.T) {
+	cards := testCards
+
+	assert.Equal(t, []repository.Card{cards[1], cards[2], cards[0]}, filterCards(cards, ""), "expected all cards ordered by name")
+	assert.Equal(t, []repository.Card{cards[1], cards[0]}, filterCards(cards, "iko"), "expected the cards of the set")
+	assert.Equal(t, []repository.Card{cards[2]}, filterCards(cards, "ELVES"), "expected the matching card")
+}
@@ -0,0 +1,214 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"progression/league"
+	"progression/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader contains the HMAC-SHA256 of the request body keyed with the shared secret, hex encoded and prefixed with "sha256=".
+	SignatureHeader = "X-Progression-Signature"
+	// EventHeader contains the name of the event, e.g. "player_joined".
+	EventHeader = "X-Progression-Event"
+	// DeliveryHeader contains the ID of the delivery. It stays the same for every retry, so receivers can ignore duplicates.
+	DeliveryHeader = "X-Progression-Delivery"
+)
+
+const (
+	// pollInterval is the time between two checks for due deliveries, e.g. retries.
+	pollInterval = 10 * time.Second
+	// deliveryTimeout is the time a webhook URL has to respond.
+	deliveryTimeout = 10 * time.Second
+	// batchSize is the maximum number of deliveries attempted per check.
+	batchSize = 50
+	// maxAttempts is the number of attempts, after which a delivery is marked as failed.
+	maxAttempts = 8
+	// baseRetryDelay is the delay before the first retry. It doubles with every further attempt up to maxRetryDelay.
+	baseRetryDelay = 30 * time.Second
+	maxRetryDelay  = time.Hour
+)
+
+// Dispatcher posts signed JSON payloads of league events to the configured webhook URLs.
+// Every delivery is kept in the delivery log of the datastore, so failed deliveries are retried with backoff, even after a restart.
+type Dispatcher struct {
+	dataStore repository.DataStore
+	client    *http.Client
+	urls      []string
+	secret    []byte
+	// wake triggers checking for due deliveries right after new ones have been logged.
+	wake chan struct{}
+	now  func() time.Time
+}
+
+func NewDispatcher(dataStore repository.DataStore, urls []string, secret string) *Dispatcher {
+	return &Dispatcher{
+		dataStore: dataStore,
+		client:    &http.Client{Timeout: deliveryTimeout},
+		urls:      urls,
+		secret:    []byte(secret),
+		wake:      make(chan struct{}, 1),
+		now:       time.Now,
+	}
+}
+
+// Handle logs a delivery of the event to every webhook URL. Subscribe it to the league's events.
+// Only joins, drops, reported matches, started and rolled back rounds, bans and unbans are delivered. The deliveries are attempted by Run.
+func (d *Dispatcher) Handle(event league.Event) {
+	data, ok := eventData(event)
+	if !ok {
+		return
+	}
+
+	now := d.now()
+	body, err := json.Marshal(Payload{Event: event.Name(), CreatedAt: now, Data: data})
+	if err != nil {
+		slog.Error("Failed to create webhook payload", "event", event.Name(), "error", err)
+		return
+	}
+
+	deliveries := make([]repository.WebhookDelivery, 0, len(d.urls))
+	for _, url := range d.urls {
+		deliveries = append(deliveries, repository.WebhookDelivery{
+			URL:           url,
+			Event:         event.Name(),
+			Payload:       string(body),
+			Status:        repository.WebhookDeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+
+	err = d.dataStore.CreateWebhookDeliveries(deliveries)
+	if err != nil {
+		slog.Error("Failed to log webhook deliveries", "event", event.Name(), "error", err)
+		return
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run attempts due deliveries every pollInterval and whenever new deliveries have been logged, until the context is cancelled.
+// Pending deliveries left over from before a restart are attempted right away.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := d.deliverDue(ctx)
+		if err != nil {
+			slog.Error("Failed to deliver webhooks", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// deliverDue attempts every due delivery once and stores the outcome in the delivery log.
+func (d *Dispatcher) deliverDue(ctx context.Context) error {
+	const errMsg = "failed to deliver due webhooks: %w"
+
+	deliveries, err := d.dataStore.GetDueWebhookDeliveries(d.now(), batchSize)
+	if err != nil {
+		return fmt.Errorf(errMsg, err)
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		d.attempt(ctx, &delivery)
+		err = d.dataStore.UpdateWebhookDelivery(delivery)
+		if err != nil {
+			return fmt.Errorf(errMsg, err)
+		}
+	}
+
+	return nil
+}
+
+// attempt posts the delivery's payload and records the outcome. Failed deliveries are scheduled for a retry until maxAttempts is reached.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *repository.WebhookDelivery) {
+	delivery.Attempts++
+
+	var err error
+	delivery.ResponseCode, err = d.post(ctx, *delivery)
+	if err == nil {
+		now := d.now()
+		delivery.Status = repository.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= maxAttempts {
+		delivery.Status = repository.WebhookDeliveryFailed
+		slog.Warn("Giving up on webhook delivery", "delivery", delivery.Id, "url", delivery.URL, "event", delivery.Event, "error", err)
+		return
+	}
+
+	delivery.NextAttemptAt = d.now().Add(retryDelay(delivery.Attempts))
+}
+
+// post sends the delivery's payload to its URL and returns the response's status code.
+func (d *Dispatcher) post(ctx context.Context, delivery repository.WebhookDelivery) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, delivery.Event)
+	request.Header.Set(DeliveryHeader, strconv.Itoa(delivery.Id))
+	request.Header.Set(SignatureHeader, Sign(d.secret, []byte(delivery.Payload)))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("%w: %d", ErrUnexpectedStatus, response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
+
+// Sign returns the value of the SignatureHeader for the given body. Receivers verify a payload by comparing it with the header.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay returns the delay before the next attempt after the given number of failed attempts.
+func retryDelay(attempts int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
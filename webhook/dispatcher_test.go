@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"progression/league"
+	"progression/repository"
+	"progression/repository/repositorytest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "secret"
+
+// receiver is a webhook URL, which responds with the given status codes in order and records every request.
+type receiver struct {
+	mutex    sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	body, _ := io.ReadAll(request.Body)
+	r.requests = append(r.requests, request)
+	r.bodies = append(r.bodies, body)
+
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func newTestDispatcher(t *testing.T, statuses ...int) (*Dispatcher, *repositorytest.MemoryDataStore, *receiver, *time.Time) {
+	t.Helper()
+
+	receiver := &receiver{statuses: statuses}
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	dataStore := repositorytest.NewMemoryDataStore()
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	dispatcher := NewDispatcher(dataStore, []string{server.URL}, testSecret)
+	dispatcher.now = func() time.Time { return now }
+	return dispatcher, dataStore, receiver, &now
+}
+
+func TestDispatcher_signed_payload(t *testing.T) {
+	dispatcher, dataStore, receiver, _ := newTestDispatcher(t)
+
+	dispatcher.Handle(league.CardBanned{CardName: "Lurrus of the Dream-Den"})
+	require.NoError(t, dispatcher.deliverDue(context.Background()))
+
+	require.Len(t, receiver.requests, 1)
+	request, body := receiver.requests[0], receiver.bodies[0]
+	assert.Equal(t, Sign([]byte(testSecret), body), request.Header.Get(SignatureHeader))
+	assert.Equal(t, "card_banned", request.Header.Get(EventHeader))
+	assert.Equal(t, "1", request.Header.Get(DeliveryHeader))
+	assert.JSONEq(t, `{"event":"card_banned","created_at":"2025-06-01T12:00:00Z","data":{"card_name":"Lurrus of the Dream-Den"}}`, string(body))
+
+	assert.Equal(t, repository.WebhookDeliveryDelivered, dataStore.WebhookDeliveries()[0].Status)
+	assert.Equal(t, http.StatusOK, dataStore.WebhookDeliveries()[0].ResponseCode)
+}
+
+func TestDispatcher_retry(t *testing.T) {
+	dispatcher, dataStore, receiver, now := newTestDispatcher(t, http.StatusInternalServerError)
+
+	dispatcher.Handle(league.PlayerJoined{UserID: "player"})
+	require.NoError(t, dispatcher.deliverDue(context.Background()))
+
+	delivery := dataStore.WebhookDeliveries()[0]
+	assert.Equal(t, repository.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, delivery.ResponseCode)
+	assert.NotEmpty(t, delivery.LastError)
+	assert.Equal(t, now.Add(baseRetryDelay), delivery.NextAttemptAt)
+
+	require.NoError(t, dispatcher.deliverDue(context.Background()))
+	assert.Len(t, receiver.requests, 1, "the retry must wait for the backoff")
+
+	*now = now.Add(baseRetryDelay)
+	require.NoError(t, dispatcher.deliverDue(context.Background()))
+	assert.Len(t, receiver.requests, 2)
+	assert.Equal(t, repository.WebhookDeliveryDelivered, dataStore.WebhookDeliveries()[0].Status)
+	assert.Equal(t, receiver.bodies[0], receiver.bodies[1], "retries must send the same payload")
+}
+
+func TestDispatcher_give_up(t *testing.T) {
+	statuses := make([]int, maxAttempts)
+	for i := range statuses {
+		statuses[i] = http.StatusServiceUnavailable
+	}
+	dispatcher, dataStore, receiver, now := newTestDispatcher(t, statuses...)
+
+	dispatcher.Handle(league.PlayerDropped{UserID: "player"})
+	for range maxAttempts + 1 {
+		require.NoError(t, dispatcher.deliverDue(context.Background()))
+		*now = now.Add(maxRetryDelay)
+	}
+
+	assert.Len(t, receiver.requests, maxAttempts)
+	assert.Equal(t, repository.WebhookDeliveryFailed, dataStore.WebhookDeliveries()[0].Status)
+	assert.Equal(t, maxAttempts, dataStore.WebhookDeliveries()[0].Attempts)
+}
+
+func TestDispatcher_ignored_event(t *testing.T) {
+	dispatcher, dataStore, _, _ := newTestDispatcher(t)
+
+	dispatcher.Handle(league.RoundEnded{Round: 1})
+	assert.Empty(t, dataStore.WebhookDeliveries())
+}
+
+func TestEventData_match_reported(t *testing.T) {
+	data, ok := eventData(league.MatchReported{
+		Pairing:    repository.Pairing{Round: 1, Player1: "a", Player2: "b", Wins1: 2, Wins2: 1},
+		ReporterID: "a",
+	})
+	require.True(t, ok)
+
+	body, err := json.Marshal(data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"round":1,"player1":"a","player2":"b","wins1":2,"wins2":1,"draws":0,"reporter_id":"a"}`, string(body))
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, baseRetryDelay, retryDelay(1))
+	assert.Equal(t, 2*baseRetryDelay, retryDelay(2))
+	assert.Equal(t, 4*baseRetryDelay, retryDelay(3))
+	assert.Equal(t, maxRetryDelay, retryDelay(maxAttempts))
+}
@@ -0,0 +1,6 @@
+package webhook
+
+import "errors"
+
+// ErrUnexpectedStatus is returned when a webhook URL responds with a status code outside of 2xx.
+var ErrUnexpectedStatus = errors.New("unexpected response status")
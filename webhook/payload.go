@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"progression/league"
+	"time"
+)
+
+// Payload is the JSON body posted to every webhook URL.
+type Payload struct {
+	// Event is the name of the league event, e.g. "player_joined".
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type playerData struct {
+	UserID string `json:"user_id"`
+}
+
+type matchData struct {
+	Round      int    `json:"round"`
+	Player1    string `json:"player1"`
+	Player2    string `json:"player2"`
+	Wins1      int    `json:"wins1"`
+	Wins2      int    `json:"wins2"`
+	Draws      int    `json:"draws"`
+	ReporterID string `json:"reporter_id"`
+}
+
+type roundData struct {
+	Round     int        `json:"round"`
+	SetCode   string     `json:"set_code"`
+	PlayerIDs []string   `json:"player_ids"`
+	Deadline  *time.Time `json:"deadline"`
+}
+
+type rollbackData struct {
+	Round    int      `json:"round"`
+	SetCodes []string `json:"set_codes"`
+}
+
+type cardData struct {
+	CardName string `json:"card_name"`
+}
+
+// eventData converts the event into the data of its payload. It returns false for events, which aren't sent to webhooks.
+func eventData(event league.Event) (any, bool) {
+	switch event := event.(type) {
+	case league.PlayerJoined:
+		return playerData{UserID: event.UserID}, true
+	case league.PlayerDropped:
+		return playerData{UserID: event.UserID}, true
+	case league.MatchReported:
+		return matchData{
+			Round:      event.Pairing.Round,
+			Player1:    event.Pairing.Player1,
+			Player2:    event.Pairing.Player2,
+			Wins1:      event.Pairing.Wins1,
+			Wins2:      event.Pairing.Wins2,
+			Draws:      event.Pairing.Draws,
+			ReporterID: event.ReporterID,
+		}, true
+	case league.RoundStarted:
+		return roundData{
+			Round:     event.Round,
+			SetCode:   event.SetCode,
+			PlayerIDs: event.PlayerIDs,
+			Deadline:  event.Deadline,
+		}, true
+	case league.RoundRolledBack:
+		return rollbackData{Round: event.Round, SetCodes: event.SetCodes}, true
+	case league.CardBanned:
+		return cardData{CardName: event.CardName}, true
+	case league.CardUnbanned:
+		return cardData{CardName: event.CardName}, true
+	default:
+		return nil, false
+	}
+}